@@ -0,0 +1,254 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"context"
+	"sync"
+)
+
+// AsyncOverflow describes the policy used by an AsyncReporter when
+// its queue is full.
+type AsyncOverflow int
+
+// Overflow policies for AsyncReporter.
+const (
+	AsyncBlock      AsyncOverflow = iota // Wait for room in the queue
+	AsyncDropNewest                      // Drop the error being reported
+	AsyncDropOldest                      // Drop the oldest queued error
+)
+
+// asyncQueueSize is the default size of the queue of an
+// AsyncReporter.
+const asyncQueueSize = 1024
+
+// AsyncReporter is a Reporter that passes on the reported errors to
+// the wrapped Reporter on a background goroutine, so that slow
+// reporters, such as those that write to a file, do not delay the
+// caller.  Errors are queued on a bounded queue; when the queue is
+// full, the overflow policy determines whether the caller waits or
+// an error is dropped.
+type AsyncReporter struct {
+	sync.Mutex
+
+	sender  sync.RWMutex  // Excludes senders while closing the queue
+	queue   chan error    // Queue of errors to pass on
+	policy  AsyncOverflow // Policy when the queue is full
+	size    int           // Size of the queue
+	pending int           // Number of errors not yet passed on
+	idle    chan struct{} // Closed when pending drops to 0
+	dropped int           // Number of errors dropped
+	closed  bool          // Set once the reporter is closed
+	done    chan struct{} // Closed when the goroutine exits
+	rep     Reporter      // Child reporter
+}
+
+// AsyncReporterOption describes an option for an AsyncReporter.
+type AsyncReporterOption func(*AsyncReporter)
+
+// AsyncQueueSize specifies the size of the queue.  The default is
+// 1024.  Sizes less than 1 are treated as 1, since the overflow
+// policies require room for at least one queued error.
+func AsyncQueueSize(size int) AsyncReporterOption {
+	return func(ar *AsyncReporter) {
+		ar.size = size
+	}
+}
+
+// AsyncPolicy specifies the policy to use when the queue is full.
+// The default is AsyncBlock, which causes the caller to wait until
+// there is room in the queue; AsyncDropNewest drops the error being
+// reported, and AsyncDropOldest drops the oldest queued error to make
+// room for it.
+func AsyncPolicy(policy AsyncOverflow) AsyncReporterOption {
+	return func(ar *AsyncReporter) {
+		ar.policy = policy
+	}
+}
+
+// NewAsyncReporter constructs a new asynchronous reporter.  An
+// asynchronous reporter queues the reported errors and passes them on
+// to the wrapped Reporter on a background goroutine, in the order
+// they were queued.  The Flush method waits until the queued errors
+// have been passed on, and the Close method, which may be called by
+// the Close helper, passes on the queued errors and stops the
+// goroutine; errors reported after the reporter is closed are passed
+// on synchronously.  The number of errors dropped because the queue
+// was full is available through the Dropped method.
+func NewAsyncReporter(rep Reporter, options ...AsyncReporterOption) *AsyncReporter {
+	obj := &AsyncReporter{
+		size: asyncQueueSize,
+		idle: make(chan struct{}),
+		done: make(chan struct{}),
+		rep:  rep,
+	}
+	close(obj.idle)
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	// Start the goroutine
+	if obj.size < 1 {
+		obj.size = 1
+	}
+	obj.queue = make(chan error, obj.size)
+	go obj.run()
+
+	return obj
+}
+
+// run passes on the queued errors to the wrapped Reporter.  It runs
+// on the background goroutine.
+func (ar *AsyncReporter) run() {
+	defer close(ar.done)
+
+	for err := range ar.queue {
+		ar.rep.Report(err)
+		ar.release(false)
+	}
+}
+
+// acquire is a helper that records an error as pending.  It returns
+// false if the reporter has been closed.
+func (ar *AsyncReporter) acquire() bool {
+	// Lock the mutex for thread safety
+	ar.Lock()
+	defer ar.Unlock()
+
+	if ar.closed {
+		return false
+	}
+
+	if ar.pending == 0 {
+		ar.idle = make(chan struct{})
+	}
+	ar.pending++
+
+	return true
+}
+
+// release is a helper that records that a pending error has been
+// passed on or, if dropped is true, dropped.
+func (ar *AsyncReporter) release(dropped bool) {
+	// Lock the mutex for thread safety
+	ar.Lock()
+	defer ar.Unlock()
+
+	if dropped {
+		ar.dropped++
+	}
+	ar.pending--
+	if ar.pending == 0 {
+		close(ar.idle)
+	}
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (ar *AsyncReporter) Report(err error) {
+	ar.sender.RLock()
+	defer ar.sender.RUnlock()
+
+	// Pass the error on synchronously once closed
+	if !ar.acquire() {
+		ar.rep.Report(err)
+		return
+	}
+
+	switch ar.policy {
+	case AsyncDropNewest:
+		select {
+		case ar.queue <- err:
+		default:
+			ar.release(true)
+		}
+
+	case AsyncDropOldest:
+		for {
+			select {
+			case ar.queue <- err:
+				return
+			default:
+			}
+
+			// Make room in the queue
+			select {
+			case <-ar.queue:
+				ar.release(true)
+			default:
+			}
+		}
+
+	default:
+		ar.queue <- err
+	}
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (ar *AsyncReporter) Unwrap() []Reporter {
+	return []Reporter{ar.rep}
+}
+
+// Dropped returns the number of errors dropped so far by the
+// asynchronous reporter because its queue was full.
+func (ar *AsyncReporter) Dropped() int {
+	// Lock the mutex for thread safety
+	ar.Lock()
+	defer ar.Unlock()
+
+	return ar.dropped
+}
+
+// Flush waits until all the errors reported so far have been passed
+// on to the wrapped Reporter or dropped.  (If errors continue to be
+// reported concurrently, Flush waits until no errors are pending.)  If
+// the context is canceled first, the context's error is returned.
+func (ar *AsyncReporter) Flush(ctx context.Context) error {
+	// Lock the mutex for thread safety
+	ar.Lock()
+	idle := ar.idle
+	ar.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close passes on the queued errors to the wrapped Reporter and stops
+// the background goroutine, waiting for it to exit.  It may safely be
+// called more than once.
+func (ar *AsyncReporter) Close() error {
+	ar.sender.Lock()
+	ar.Lock()
+	closed := ar.closed
+	ar.closed = true
+	ar.Unlock()
+	if !closed {
+		close(ar.queue)
+	}
+	ar.sender.Unlock()
+
+	<-ar.done
+
+	return nil
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingReporter returns a MockReporter whose Report method signals
+// on started and then waits for release to be closed.
+func blockingReporter(started chan<- error, release <-chan struct{}) *MockReporter {
+	rep := &MockReporter{}
+	rep.On("Report", mock.Anything).Run(func(args mock.Arguments) {
+		started <- args.Get(0).(error)
+		<-release
+	})
+
+	return rep
+}
+
+func TestAsyncReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &AsyncReporter{})
+}
+
+func TestAsyncQueueSize(t *testing.T) {
+	obj := &AsyncReporter{}
+
+	opt := AsyncQueueSize(5)
+	opt(obj)
+
+	assert.Equal(t, 5, obj.size)
+}
+
+func TestAsyncPolicy(t *testing.T) {
+	obj := &AsyncReporter{}
+
+	opt := AsyncPolicy(AsyncDropOldest)
+	opt(obj)
+
+	assert.Equal(t, AsyncDropOldest, obj.policy)
+}
+
+func TestNewAsyncReporterBase(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewAsyncReporter(rep)
+	defer result.Close()
+
+	assert.Equal(t, asyncQueueSize, result.size)
+	assert.Equal(t, asyncQueueSize, cap(result.queue))
+	assert.Equal(t, AsyncBlock, result.policy)
+	assert.Same(t, rep, result.rep)
+	assert.NoError(t, result.Flush(context.Background()))
+}
+
+func TestNewAsyncReporterOptions(t *testing.T) {
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *AsyncReporter
+	options := []AsyncReporterOption{
+		func(ar *AsyncReporter) {
+			opt1Called = ar
+		},
+		func(ar *AsyncReporter) {
+			opt2Called = ar
+		},
+		AsyncQueueSize(-1),
+	}
+
+	result := NewAsyncReporter(rep, options...)
+	defer result.Close()
+
+	assert.Equal(t, 1, result.size)
+	assert.Equal(t, 1, cap(result.queue))
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestAsyncReporterReport(t *testing.T) {
+	rep := NewCapturingReporter(Root())
+	obj := NewAsyncReporter(rep)
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+
+	obj.Report(err1)
+	obj.Report(err2)
+
+	require.NoError(t, obj.Flush(context.Background()))
+	assert.Equal(t, []error{err1, err2}, rep.List())
+	assert.Equal(t, 0, obj.Dropped())
+	require.NoError(t, obj.Close())
+}
+
+func TestAsyncReporterReportBlock(t *testing.T) {
+	started := make(chan error, 3)
+	release := make(chan struct{})
+	rep := blockingReporter(started, release)
+	obj := NewAsyncReporter(rep, AsyncQueueSize(1))
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+	obj.Report(err1)
+	assert.Same(t, err1, <-started)
+	obj.Report(err2)
+
+	reported := make(chan struct{})
+	go func() {
+		obj.Report(err3)
+		close(reported)
+	}()
+	select {
+	case <-reported:
+		t.Fatal("Report did not block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+	<-reported
+
+	require.NoError(t, obj.Close())
+	assert.Same(t, err2, <-started)
+	assert.Same(t, err3, <-started)
+	assert.Equal(t, 0, obj.Dropped())
+}
+
+func TestAsyncReporterReportDropNewest(t *testing.T) {
+	started := make(chan error, 3)
+	release := make(chan struct{})
+	rep := blockingReporter(started, release)
+	obj := NewAsyncReporter(rep, AsyncQueueSize(1), AsyncPolicy(AsyncDropNewest))
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+	obj.Report(err1)
+	assert.Same(t, err1, <-started)
+
+	obj.Report(err2)
+	obj.Report(err3)
+
+	assert.Equal(t, 1, obj.Dropped())
+	close(release)
+	require.NoError(t, obj.Close())
+	assert.Same(t, err2, <-started)
+	assert.Len(t, started, 0)
+}
+
+func TestAsyncReporterReportQueueSizeZero(t *testing.T) {
+	started := make(chan error, 3)
+	release := make(chan struct{})
+	rep := blockingReporter(started, release)
+	obj := NewAsyncReporter(rep, AsyncQueueSize(0), AsyncPolicy(AsyncDropNewest))
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+	obj.Report(err1)
+	assert.Same(t, err1, <-started)
+
+	obj.Report(err2)
+	obj.Report(err3)
+
+	assert.Equal(t, 1, cap(obj.queue))
+	assert.Equal(t, 1, obj.Dropped())
+	close(release)
+	require.NoError(t, obj.Close())
+	assert.Same(t, err2, <-started)
+	assert.Len(t, started, 0)
+}
+
+func TestAsyncReporterReportDropOldest(t *testing.T) {
+	started := make(chan error, 3)
+	release := make(chan struct{})
+	rep := blockingReporter(started, release)
+	obj := NewAsyncReporter(rep, AsyncQueueSize(1), AsyncPolicy(AsyncDropOldest))
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+	obj.Report(err1)
+	assert.Same(t, err1, <-started)
+
+	obj.Report(err2)
+	obj.Report(err3)
+
+	assert.Equal(t, 1, obj.Dropped())
+	close(release)
+	require.NoError(t, obj.Close())
+	assert.Same(t, err3, <-started)
+	assert.Len(t, started, 0)
+}
+
+func TestAsyncReporterReportClosed(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError)
+	obj := NewAsyncReporter(rep)
+	require.NoError(t, obj.Close())
+
+	obj.Report(assert.AnError)
+
+	rep.AssertExpectations(t)
+}
+
+func TestAsyncReporterReportConcurrent(t *testing.T) {
+	counter := NewCountingReporter(Root())
+	obj := NewAsyncReporter(counter, AsyncQueueSize(4), AsyncPolicy(AsyncDropOldest))
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				obj.Report(assert.AnError)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, obj.Close())
+	assert.Equal(t, 1000, counter.Errors()+obj.Dropped())
+}
+
+func TestAsyncReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &AsyncReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestAsyncReporterDropped(t *testing.T) {
+	obj := &AsyncReporter{
+		dropped: 42,
+	}
+
+	result := obj.Dropped()
+
+	assert.Equal(t, 42, result)
+}
+
+func TestAsyncReporterFlushCanceled(t *testing.T) {
+	started := make(chan error, 1)
+	release := make(chan struct{})
+	rep := blockingReporter(started, release)
+	obj := NewAsyncReporter(rep)
+	obj.Report(assert.AnError)
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := obj.Flush(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	close(release)
+	require.NoError(t, obj.Flush(context.Background()))
+	require.NoError(t, obj.Close())
+}
+
+func TestAsyncReporterClose(t *testing.T) {
+	rep := NewCapturingReporter(Root())
+	obj := NewAsyncReporter(rep)
+	obj.Report(assert.AnError)
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.True(t, obj.closed)
+	assert.Equal(t, []error{assert.AnError}, rep.List())
+	assert.NoError(t, obj.Close())
+}
+
+func TestAsyncReporterCloseHelper(t *testing.T) {
+	rep := NewCapturingReporter(Root())
+	obj := NewAsyncReporter(rep)
+	obj.Report(assert.AnError)
+
+	err := Close(obj)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{assert.AnError}, rep.List())
+}
@@ -95,7 +95,9 @@ func (cr *CapturingReporter) Unwrap() []Reporter {
 }
 
 // List returns the list of captured errors reported using the
-// CapturingReporter.
+// CapturingReporter.  The errors are returned as they were reported,
+// so information such as the notes attached to an error remains
+// available using helpers such as Notes.
 func (cr *CapturingReporter) List() []error {
 	// Lock the mutex for thread safety
 	cr.Lock()
@@ -0,0 +1,342 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Localizable is a utility interface for errors constructed from a
+// message ID and arguments, such as those constructed by Catalog.New.
+// Errors implementing Localizable are discoverable using errors.As,
+// or by using the MessageOf helper.
+type Localizable interface {
+	error
+
+	// MessageID returns the ID of the message.
+	MessageID() string
+
+	// MessageArgs returns the arguments of the message.
+	MessageArgs() []interface{}
+}
+
+// messageError is an implementation of Localizable.
+type messageError struct {
+	id   string        // The message ID
+	args []interface{} // The message arguments
+	err  error         // The message, as formatted by fmt.Errorf
+}
+
+// Error returns the error message.
+func (me *messageError) Error() string {
+	return me.err.Error()
+}
+
+// MessageID returns the ID of the message.
+func (me *messageError) MessageID() string {
+	return me.id
+}
+
+// MessageArgs returns the arguments of the message.
+func (me *messageError) MessageArgs() []interface{} {
+	return me.args
+}
+
+// Unwrap returns the wrapped error.  This is the error constructed by
+// fmt.Errorf, which in turn wraps any errors passed to a %w verb.
+func (me *messageError) Unwrap() error {
+	return me.err
+}
+
+// MessageOf retrieves the message ID and arguments of an error.  It
+// returns the ID and arguments of the first error in the chain that
+// implements Localizable, along with a boolean true; if the error
+// was not constructed from a message ID, it returns false.
+func MessageOf(err error) (string, []interface{}, bool) {
+	var le Localizable
+	if errors.As(err, &le) {
+		return le.MessageID(), le.MessageArgs(), true
+	}
+
+	return "", nil, false
+}
+
+// normLocale is a helper that normalizes a locale name, such as
+// "pt_BR", to lower case with hyphen separators, such as "pt-br".
+func normLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// Catalog is a catalog of message formats, keyed by message ID, in
+// one or more locales.  Messages are formatted as for fmt.Errorf;
+// translations may use explicit argument indexes, such as %[2]s, to
+// reorder the arguments.  Locale names are case-insensitive, and "_"
+// is equivalent to "-".
+type Catalog struct {
+	sync.RWMutex
+
+	lang     string                       // The default locale
+	messages map[string]map[string]string // Message formats by locale
+}
+
+// NewCatalog constructs a new, empty Catalog with the specified
+// default locale, such as "en".  Messages not available in a
+// requested locale fall back to the default locale.
+func NewCatalog(lang string) *Catalog {
+	return &Catalog{
+		lang:     normLocale(lang),
+		messages: map[string]map[string]string{},
+	}
+}
+
+// Add adds message formats, keyed by message ID, to the catalog for
+// the specified locale.  Formats for message IDs already in the
+// catalog for that locale are replaced.
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	locale = normLocale(locale)
+
+	// Lock the mutex for thread safety
+	c.Lock()
+	defer c.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]string{}
+	}
+	for id, format := range messages {
+		c.messages[locale][id] = format
+	}
+}
+
+// CatalogDecoder describes a function that decodes a document, such
+// as json.Unmarshal.  It will be passed the contents of the document
+// and a pointer to a map of message formats keyed by message ID, and
+// must decode the document into the map.
+type CatalogDecoder func(data []byte, v interface{}) error
+
+// Load loads message formats for the specified locale from a document
+// mapping message IDs to formats, decoded using the specified
+// CatalogDecoder.
+func (c *Catalog) Load(locale string, data []byte, decode CatalogDecoder) error {
+	messages := map[string]string{}
+	if err := decode(data, &messages); err != nil {
+		return err
+	}
+
+	c.Add(locale, messages)
+
+	return nil
+}
+
+// LoadJSON loads message formats for the specified locale from a JSON
+// object mapping message IDs to formats.
+func (c *Catalog) LoadJSON(locale string, data []byte) error {
+	return c.Load(locale, data, json.Unmarshal)
+}
+
+// LoadFS loads message formats from the files in the named directory
+// of a file system, such as an embed.FS.  Each file named
+// "<locale>.json", such as "fr.json", is loaded as JSON for the
+// locale given by its name; other files are ignored.  To load
+// documents in other formats, such as TOML, use LoadFSWith.
+func (c *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	return c.LoadFSWith(fsys, dir, nil)
+}
+
+// LoadFSWith is similar to LoadFS, except that documents in other
+// formats may be loaded by passing a map of CatalogDecoder functions
+// keyed by file extension, including the ".", such as ".toml"; these
+// may also override the decoder for ".json".  Files with other
+// extensions are ignored.
+func (c *Catalog) LoadFSWith(fsys fs.FS, dir string, decoders map[string]CatalogDecoder) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		// Select the decoder
+		name := entry.Name()
+		ext := path.Ext(name)
+		decode, ok := decoders[ext]
+		if !ok {
+			if ext != ".json" {
+				continue
+			}
+			decode = json.Unmarshal
+		}
+
+		// Load the file
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if err := c.Load(strings.TrimSuffix(name, ext), data, decode); err != nil {
+			return fmt.Errorf("%s: %w", path.Join(dir, name), err)
+		}
+	}
+
+	return nil
+}
+
+// Lookup looks up the format of a message in the specified locale.
+// If the message is not available in the locale, the base language
+// of the locale (e.g., "pt" for "pt-BR") is tried, followed by the
+// default locale of the catalog.
+func (c *Catalog) Lookup(locale, id string) (string, bool) {
+	locale = normLocale(locale)
+	candidates := []string{locale}
+	if idx := strings.IndexByte(locale, '-'); idx > 0 {
+		candidates = append(candidates, locale[:idx])
+	}
+	candidates = append(candidates, c.lang)
+
+	// Lock the mutex for thread safety
+	c.RLock()
+	defer c.RUnlock()
+
+	for _, cand := range candidates {
+		if format, ok := c.messages[cand][id]; ok {
+			return format, true
+		}
+	}
+
+	return "", false
+}
+
+// format is a helper that formats a message in the specified locale,
+// as an error constructed by fmt.Errorf.  If the message is not in
+// the catalog, the message ID is used, followed by the arguments.
+func (c *Catalog) format(locale, id string, args []interface{}) error {
+	format, ok := c.Lookup(locale, id)
+	if !ok {
+		if len(args) == 0 {
+			return errors.New(id) //nolint:goerr113
+		}
+		return fmt.Errorf("%s %v", id, args) //nolint:goerr113
+	}
+
+	return fmt.Errorf(format, args...) //nolint:goerr113
+}
+
+// Localize formats a message in the specified locale.
+func (c *Catalog) Localize(locale, id string, args ...interface{}) string {
+	return c.format(locale, id, args).Error()
+}
+
+// New constructs a new error from a message ID and arguments.  The
+// error message is formatted in the default locale of the catalog;
+// the message may be rendered in another locale by passing the
+// FormatCatalog option to a reporter.  The message ID and arguments
+// may be retrieved using MessageOf.  As with fmt.Errorf, any errors
+// passed to a %w verb are wrapped by the returned error.
+func (c *Catalog) New(id string, args ...interface{}) error {
+	return &messageError{
+		id:   id,
+		args: args,
+		err:  c.format(c.lang, id, args),
+	}
+}
+
+// localizedError is an error with a localized message.  It wraps the
+// original error, so all the information attached to the original
+// error remains available.
+type localizedError struct {
+	msg string // The localized message
+	err error  // The original error
+}
+
+// Error returns the localized error message.
+func (le *localizedError) Error() string {
+	return le.msg
+}
+
+// Unwrap returns the original error.
+func (le *localizedError) Unwrap() error {
+	return le.err
+}
+
+// localize is a helper that renders the message of an error in the
+// specified locale, if the error was constructed from a message ID.
+// The message is rebuilt by walking the chain of wrapped errors from
+// the error constructed from the message ID outward.  Wrappers that
+// delegate to the wrapped error's message, such as those constructed
+// by WithPosition, and wrappers that prefix the wrapped error's
+// message, such as those constructed by fmt.Errorf with a trailing
+// %w verb, are preserved.  If any wrapper renders the wrapped message
+// in some other way, or wraps multiple errors, the error is returned
+// unchanged.
+func (c *Catalog) localize(locale string, err error) error {
+	// Find the chain of errors leading to the message
+	chain := []error{}
+	var le Localizable
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if l, ok := e.(Localizable); ok {
+			le = l
+			break
+		}
+		chain = append(chain, e)
+	}
+	if le == nil {
+		return err
+	}
+
+	// Rebuild the message from the inside out
+	msg := c.Localize(locale, le.MessageID(), le.MessageArgs()...)
+	inner := le.Error()
+	for i := len(chain) - 1; i >= 0; i-- {
+		outer := chain[i].Error()
+		switch {
+		case outer == inner:
+		case strings.HasSuffix(outer, inner):
+			msg = outer[:len(outer)-len(inner)] + msg
+		default:
+			return err
+		}
+		inner = outer
+	}
+
+	return &localizedError{
+		msg: msg,
+		err: err,
+	}
+}
+
+// FormatCatalog specifies that errors constructed from a message ID,
+// such as by Catalog.New, should be rendered in the specified locale
+// using the specified catalog.  Messages not available in the locale
+// fall back to the default locale of the catalog.  This applies to
+// all formats, including those set by other options, such as
+// FormatError; the formats are passed an error with the localized
+// message that wraps the original error.  Context added by wrapping
+// the error, such as with fmt.Errorf("context: %w", err), is
+// preserved; errors wrapped in other ways, such as with a %w verb
+// that is not at the end of the format, or with errors.Join, are not
+// localized.
+func FormatCatalog(cat *Catalog, locale string) FormatOption {
+	return func(f *Formatters) {
+		f.catalog = cat
+		f.locale = locale
+	}
+}
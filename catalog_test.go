@@ -0,0 +1,417 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageErrorImplementsLocalizable(t *testing.T) {
+	assert.Implements(t, (*Localizable)(nil), &messageError{})
+}
+
+func TestMessageErrorError(t *testing.T) {
+	obj := &messageError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestMessageErrorMessageID(t *testing.T) {
+	obj := &messageError{
+		id: "test-id",
+	}
+
+	result := obj.MessageID()
+
+	assert.Equal(t, "test-id", result)
+}
+
+func TestMessageErrorMessageArgs(t *testing.T) {
+	obj := &messageError{
+		args: []interface{}{"a", 1},
+	}
+
+	result := obj.MessageArgs()
+
+	assert.Equal(t, []interface{}{"a", 1}, result)
+}
+
+func TestMessageErrorUnwrap(t *testing.T) {
+	obj := &messageError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestMessageOfFound(t *testing.T) {
+	err := fmt.Errorf("context: %w", &messageError{id: "test-id", args: []interface{}{1}, err: assert.AnError})
+
+	id, args, ok := MessageOf(err)
+
+	assert.True(t, ok)
+	assert.Equal(t, "test-id", id)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestMessageOfMissing(t *testing.T) {
+	id, args, ok := MessageOf(assert.AnError)
+
+	assert.False(t, ok)
+	assert.Equal(t, "", id)
+	assert.Nil(t, args)
+}
+
+func TestNormLocale(t *testing.T) {
+	assert.Equal(t, "pt-br", normLocale("pt_BR"))
+}
+
+func TestNewCatalog(t *testing.T) {
+	result := NewCatalog("EN")
+
+	assert.Equal(t, &Catalog{
+		lang:     "en",
+		messages: map[string]map[string]string{},
+	}, result)
+}
+
+func TestCatalogAdd(t *testing.T) {
+	obj := &Catalog{
+		messages: map[string]map[string]string{
+			"fr": {"a": "old a", "b": "b"},
+		},
+	}
+
+	obj.Add("FR", map[string]string{"a": "new a", "c": "c"})
+	obj.Add("de", map[string]string{"a": "de a"})
+
+	assert.Equal(t, map[string]map[string]string{
+		"fr": {"a": "new a", "b": "b", "c": "c"},
+		"de": {"a": "de a"},
+	}, obj.messages)
+}
+
+func TestCatalogLoadJSON(t *testing.T) {
+	obj := NewCatalog("en")
+
+	err := obj.LoadJSON("fr", []byte(`{"dup": "clé en double %q"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"fr": {"dup": "clé en double %q"},
+	}, obj.messages)
+}
+
+func TestCatalogLoadJSONError(t *testing.T) {
+	obj := NewCatalog("en")
+
+	err := obj.LoadJSON("fr", []byte(`{"dup": 1}`))
+
+	assert.Error(t, err)
+	assert.Equal(t, map[string]map[string]string{}, obj.messages)
+}
+
+func TestCatalogLoad(t *testing.T) {
+	obj := NewCatalog("en")
+
+	err := obj.Load("fr", []byte("dup=clé en double %q"), func(data []byte, v interface{}) error {
+		key, value, _ := strings.Cut(string(data), "=")
+		(*v.(*map[string]string))[key] = value
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"fr": {"dup": "clé en double %q"},
+	}, obj.messages)
+}
+
+func TestCatalogLoadError(t *testing.T) {
+	obj := NewCatalog("en")
+
+	err := obj.Load("fr", []byte("dup"), func(data []byte, v interface{}) error {
+		return assert.AnError
+	})
+
+	assert.Same(t, assert.AnError, err)
+	assert.Equal(t, map[string]map[string]string{}, obj.messages)
+}
+
+// lineDecoder is a CatalogDecoder for testing that decodes lines of
+// the form "key=value".
+func lineDecoder(data []byte, v interface{}) error {
+	messages := v.(*map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("bad line %q", line) //nolint:goerr113
+		}
+		(*messages)[key] = value
+	}
+
+	return nil
+}
+
+func TestCatalogLoadFSWith(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msgs/en.json":     &fstest.MapFile{Data: []byte(`{"dup": "duplicate key %q"}`)},
+		"msgs/fr.txt":      &fstest.MapFile{Data: []byte(`dup=clé en double %q`)},
+		"msgs/README":      &fstest.MapFile{Data: []byte("ignored")},
+		"msgs/sub/de.json": &fstest.MapFile{Data: []byte(`{"dup": "ignored"}`)},
+	}
+	obj := NewCatalog("en")
+
+	err := obj.LoadFSWith(fsys, "msgs", map[string]CatalogDecoder{".txt": lineDecoder})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"en": {"dup": "duplicate key %q"},
+		"fr": {"dup": "clé en double %q"},
+	}, obj.messages)
+}
+
+func TestCatalogLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msgs/en.json": &fstest.MapFile{Data: []byte(`{"dup": "duplicate key %q"}`)},
+		"msgs/fr.toml": &fstest.MapFile{Data: []byte(`dup = "clé en double %q"`)},
+	}
+	obj := NewCatalog("en")
+
+	err := obj.LoadFS(fsys, "msgs")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"en": {"dup": "duplicate key %q"},
+	}, obj.messages)
+}
+
+func TestCatalogLoadFSWithOverrideJSON(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msgs/fr.json": &fstest.MapFile{Data: []byte(`dup=clé en double %q`)},
+	}
+	obj := NewCatalog("en")
+
+	err := obj.LoadFSWith(fsys, "msgs", map[string]CatalogDecoder{".json": lineDecoder})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"fr": {"dup": "clé en double %q"},
+	}, obj.messages)
+}
+
+func TestCatalogLoadFSMissingDir(t *testing.T) {
+	obj := NewCatalog("en")
+
+	err := obj.LoadFS(fstest.MapFS{}, "msgs")
+
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCatalogLoadFSBadFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msgs/fr.json": &fstest.MapFile{Data: []byte(`{"dup": `)},
+	}
+	obj := NewCatalog("en")
+
+	err := obj.LoadFS(fsys, "msgs")
+
+	var synErr *json.SyntaxError
+	assert.ErrorAs(t, err, &synErr)
+	assert.Contains(t, err.Error(), "msgs/fr.json: ")
+}
+
+// testCatalog is a helper that constructs a catalog for testing.
+func testCatalog() *Catalog {
+	obj := NewCatalog("en")
+	obj.Add("en", map[string]string{
+		"dup":   "duplicate key %q",
+		"order": "%s then %s",
+		"wrap":  "cannot load: %w",
+	})
+	obj.Add("pt", map[string]string{
+		"dup": "chave duplicada %q",
+	})
+	obj.Add("pt-BR", map[string]string{
+		"order": "%[2]s depois %[1]s",
+	})
+
+	return obj
+}
+
+func TestCatalogLookup(t *testing.T) {
+	obj := testCatalog()
+
+	for _, tc := range []struct {
+		locale, id, format string
+		ok                 bool
+	}{
+		{"pt_BR", "order", "%[2]s depois %[1]s", true},
+		{"pt_BR", "dup", "chave duplicada %q", true},
+		{"pt_BR", "wrap", "cannot load: %w", true},
+		{"fr", "dup", "duplicate key %q", true},
+		{"fr", "missing", "", false},
+	} {
+		format, ok := obj.Lookup(tc.locale, tc.id)
+
+		assert.Equal(t, tc.format, format, "%s/%s", tc.locale, tc.id)
+		assert.Equal(t, tc.ok, ok, "%s/%s", tc.locale, tc.id)
+	}
+}
+
+func TestCatalogLocalize(t *testing.T) {
+	obj := testCatalog()
+
+	assert.Equal(t, "b depois a", obj.Localize("pt-BR", "order", "a", "b"))
+	assert.Equal(t, "missing", obj.Localize("pt-BR", "missing"))
+	assert.Equal(t, "missing [a 1]", obj.Localize("pt-BR", "missing", "a", 1))
+}
+
+func TestCatalogNew(t *testing.T) {
+	obj := testCatalog()
+
+	result := obj.New("dup", "foo")
+
+	assert.Equal(t, `duplicate key "foo"`, result.Error())
+	id, args, ok := MessageOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, "dup", id)
+	assert.Equal(t, []interface{}{"foo"}, args)
+}
+
+func TestCatalogNewWrap(t *testing.T) {
+	obj := testCatalog()
+
+	result := obj.New("wrap", assert.AnError)
+
+	assert.Equal(t, "cannot load: "+assert.AnError.Error(), result.Error())
+	assert.ErrorIs(t, result, assert.AnError)
+}
+
+func TestLocalizedErrorError(t *testing.T) {
+	obj := &localizedError{
+		msg: "localized",
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, "localized", result)
+}
+
+func TestLocalizedErrorUnwrap(t *testing.T) {
+	obj := &localizedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestCatalogLocalizeErrorPlain(t *testing.T) {
+	obj := testCatalog()
+
+	result := obj.localize("pt", assert.AnError)
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestCatalogLocalizeErrorWrapped(t *testing.T) {
+	obj := testCatalog()
+	err := fmt.Errorf("file.cfg: %w", WithPosition(obj.New("dup", "foo"), Position{Line: 3}))
+
+	result := obj.localize("pt", err)
+
+	assert.Equal(t, `file.cfg: chave duplicada "foo"`, result.Error())
+	assert.True(t, errors.Is(result, err))
+	pos, ok := PositionOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, Position{Line: 3}, pos)
+}
+
+func TestCatalogLocalizeErrorUnwrapped(t *testing.T) {
+	obj := testCatalog()
+	err := obj.New("dup", "foo")
+
+	result := obj.localize("pt", err)
+
+	assert.Equal(t, `chave duplicada "foo"`, result.Error())
+	assert.True(t, errors.Is(result, err))
+}
+
+func TestCatalogLocalizeErrorNested(t *testing.T) {
+	obj := testCatalog()
+	err := fmt.Errorf("load: %w", WithCode(fmt.Errorf("file.cfg: %w", obj.New("dup", "foo")), "CFG1003"))
+
+	result := obj.localize("pt", err)
+
+	assert.Equal(t, `load: file.cfg: chave duplicada "foo"`, result.Error())
+	code, ok := CodeOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, "CFG1003", code)
+}
+
+func TestCatalogLocalizeErrorRepeated(t *testing.T) {
+	obj := testCatalog()
+	obj.Add("en", map[string]string{"key": "key %s"})
+	obj.Add("pt", map[string]string{"key": "chave %s"})
+	err := fmt.Errorf("key key: %w", obj.New("key", "key"))
+
+	result := obj.localize("pt", err)
+
+	assert.Equal(t, "key key: chave key", result.Error())
+}
+
+func TestCatalogLocalizeErrorSuffixed(t *testing.T) {
+	obj := testCatalog()
+	err := fmt.Errorf("%w (in file.cfg)", obj.New("dup", "foo"))
+
+	result := obj.localize("pt", err)
+
+	assert.Same(t, err, result)
+}
+
+func TestCatalogLocalizeErrorJoined(t *testing.T) {
+	obj := testCatalog()
+	err := errors.Join(obj.New("dup", "foo"), assert.AnError)
+
+	result := obj.localize("pt", err)
+
+	assert.Same(t, err, result)
+}
+
+func TestFormatCatalog(t *testing.T) {
+	cat := NewCatalog("en")
+	obj := &Formatters{}
+
+	opt := FormatCatalog(cat, "fr")
+	opt(obj)
+
+	assert.Same(t, cat, obj.catalog)
+	assert.Equal(t, "fr", obj.locale)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/xml"
+	"io"
+	"sync"
+)
+
+// checkstyleVersion is the version of the Checkstyle format emitted
+// by CheckstyleReporter.
+const checkstyleVersion = "4.3"
+
+// checkstyleError describes a Checkstyle error element.
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr,omitempty"`
+}
+
+// checkstyleFile describes a Checkstyle file element.
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+// checkstyleDoc describes the top-level Checkstyle element.
+type checkstyleDoc struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+// checkstyleSeverity maps a severity to a Checkstyle severity.
+func checkstyleSeverity(sev Severity) string {
+	switch {
+	case sev >= SeverityError:
+		return "error"
+	case sev == SeverityWarning:
+		return "warning"
+	case sev >= SeverityInfo:
+		return "info"
+	}
+
+	return "ignore"
+}
+
+// CheckstyleReporter is a Reporter that accumulates the errors and
+// warnings reported using it, and writes them to a specified
+// io.Writer stream as a Checkstyle XML document when it is closed.
+type CheckstyleReporter struct {
+	sync.Mutex
+
+	out    io.Writer                    // The output stream to write to
+	pseudo string                       // File name for errors without one
+	files  []string                     // The files, in order of appearance
+	errs   map[string][]checkstyleError // The reported errors, by file
+	closed bool                         // Set when the document has been written
+	rep    Reporter                     // Child reporter
+}
+
+// CheckstyleReporterOption describes an option for a
+// CheckstyleReporter.
+type CheckstyleReporterOption func(*CheckstyleReporter)
+
+// CheckstylePseudoFile specifies the file name to use for errors
+// that do not have a position with a file name.  The default is "-".
+func CheckstylePseudoFile(name string) CheckstyleReporterOption {
+	return func(cr *CheckstyleReporter) {
+		cr.pseudo = name
+	}
+}
+
+// NewCheckstyleReporter constructs a new Checkstyle reporter.  A
+// Checkstyle reporter accumulates the reported errors and warnings,
+// grouped by the file of their position (see PositionOf), and writes
+// a Checkstyle XML document to the specified output stream when its
+// Close method is called, such as by the Close helper.  The severity
+// of each error is mapped to the corresponding Checkstyle severity,
+// and its diagnostic code, if it has one, is used as the source.
+func NewCheckstyleReporter(out io.Writer, rep Reporter, options ...CheckstyleReporterOption) *CheckstyleReporter {
+	obj := &CheckstyleReporter{
+		out:    out,
+		pseudo: "-",
+		errs:   map[string][]checkstyleError{},
+		rep:    rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (cr *CheckstyleReporter) Report(err error) {
+	// Construct the error element
+	name := cr.pseudo
+	elem := checkstyleError{
+		Severity: checkstyleSeverity(SeverityOf(err)),
+		Message:  err.Error(),
+	}
+	if pos, ok := PositionOf(err); ok && pos.File != "" {
+		name = pos.File
+		elem.Line = pos.Line
+		elem.Column = pos.Column
+	}
+	elem.Source, _ = CodeOf(err)
+
+	cr.Lock()
+	if _, ok := cr.errs[name]; !ok {
+		cr.files = append(cr.files, name)
+	}
+	cr.errs[name] = append(cr.errs[name], elem)
+	cr.Unlock()
+
+	cr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (cr *CheckstyleReporter) Unwrap() []Reporter {
+	return []Reporter{cr.rep}
+}
+
+// Close writes the Checkstyle XML document to the output stream.  The
+// document is only written once; subsequent calls to Close do
+// nothing.
+func (cr *CheckstyleReporter) Close() error {
+	// Lock the mutex for thread safety
+	cr.Lock()
+	defer cr.Unlock()
+
+	if cr.closed {
+		return nil
+	}
+	cr.closed = true
+
+	// Construct the document
+	doc := &checkstyleDoc{
+		Version: checkstyleVersion,
+	}
+	for _, name := range cr.files {
+		doc.Files = append(doc.Files, checkstyleFile{
+			Name:   name,
+			Errors: cr.errs[name],
+		})
+	}
+
+	// Write the document
+	if _, err := io.WriteString(cr.out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(cr.out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(cr.out, "\n")
+
+	return err
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckstyleSeverity(t *testing.T) {
+	assert.Equal(t, "ignore", checkstyleSeverity(SeverityDebug))
+	assert.Equal(t, "info", checkstyleSeverity(SeverityInfo))
+	assert.Equal(t, "info", checkstyleSeverity(SeverityNotice))
+	assert.Equal(t, "warning", checkstyleSeverity(SeverityWarning))
+	assert.Equal(t, "error", checkstyleSeverity(SeverityError))
+	assert.Equal(t, "error", checkstyleSeverity(SeverityFatal))
+}
+
+func TestCheckstyleReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &CheckstyleReporter{})
+}
+
+func TestCheckstylePseudoFile(t *testing.T) {
+	obj := &CheckstyleReporter{}
+
+	opt := CheckstylePseudoFile("config")
+	opt(obj)
+
+	assert.Equal(t, &CheckstyleReporter{
+		pseudo: "config",
+	}, obj)
+}
+
+func TestNewCheckstyleReporterBase(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+
+	result := NewCheckstyleReporter(out, rep)
+
+	assert.Equal(t, &CheckstyleReporter{
+		out:    out,
+		pseudo: "-",
+		errs:   map[string][]checkstyleError{},
+		rep:    rep,
+	}, result)
+}
+
+func TestNewCheckstyleReporterOptions(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *CheckstyleReporter
+	options := []CheckstyleReporterOption{
+		func(cr *CheckstyleReporter) {
+			opt1Called = cr
+		},
+		func(cr *CheckstyleReporter) {
+			opt2Called = cr
+		},
+	}
+
+	result := NewCheckstyleReporter(out, rep, options...)
+
+	assert.Equal(t, &CheckstyleReporter{
+		out:    out,
+		pseudo: "-",
+		errs:   map[string][]checkstyleError{},
+		rep:    rep,
+	}, result)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestCheckstyleReporterReport(t *testing.T) {
+	err1 := WithCode(ErrorAt(Position{File: "a.cfg", Line: 3, Column: 5}, "test error"), "CFG1001")
+	err2 := WithPosition(NewWarning("test warning"), Position{Line: 7})
+	err3 := WarningAt(Position{File: "a.cfg", Line: 9}, "another warning")
+	rep := &MockReporter{}
+	rep.On("Report", err1)
+	rep.On("Report", err2)
+	rep.On("Report", err3)
+	obj := &CheckstyleReporter{
+		pseudo: "config",
+		errs:   map[string][]checkstyleError{},
+		rep:    rep,
+	}
+
+	obj.Report(err1)
+	obj.Report(err2)
+	obj.Report(err3)
+
+	assert.Equal(t, []string{"a.cfg", "config"}, obj.files)
+	assert.Equal(t, map[string][]checkstyleError{
+		"a.cfg": {
+			{
+				Line:     3,
+				Column:   5,
+				Severity: "error",
+				Message:  "test error",
+				Source:   "CFG1001",
+			},
+			{
+				Line:     9,
+				Severity: "warning",
+				Message:  "another warning",
+			},
+		},
+		"config": {
+			{
+				Severity: "warning",
+				Message:  "test warning",
+			},
+		},
+	}, obj.errs)
+	rep.AssertExpectations(t)
+}
+
+func TestCheckstyleReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &CheckstyleReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestCheckstyleReporterClose(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewCheckstyleReporter(out, Root())
+	obj.Report(WithCode(ErrorAt(Position{File: "a.cfg", Line: 3, Column: 5}, "bad \"value\""), "CFG1001"))
+	obj.Report(NewSeverity(SeverityNotice, "test notice"))
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="a.cfg">
+    <error line="3" column="5" severity="error" message="bad &#34;value&#34;" source="CFG1001"></error>
+  </file>
+  <file name="-">
+    <error severity="info" message="test notice"></error>
+  </file>
+</checkstyle>
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestCheckstyleReporterCloseEmpty(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewCheckstyleReporter(out, Root())
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<checkstyle version=\"4.3\"></checkstyle>\n", out.String())
+}
+
+func TestCheckstyleReporterCloseTwice(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := &CheckstyleReporter{
+		out:    out,
+		closed: true,
+	}
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+}
+
+func TestCheckstyleReporterCloseWriteError(t *testing.T) {
+	obj := NewCheckstyleReporter(&failingWriter{}, Root())
+
+	err := obj.Close()
+
+	assert.Same(t, assert.AnError, err)
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Errors that may be returned by Registry methods.
+var (
+	ErrEmptyCode     = errors.New("diagnostic code must not be empty")
+	ErrDuplicateCode = errors.New("diagnostic code already registered")
+	ErrUnknownCode   = errors.New("unknown diagnostic code")
+)
+
+// Coded is a utility interface for errors that carry a stable
+// diagnostic code, such as "CFG1003".  Errors implementing Coded are
+// discoverable using errors.As, or by using the CodeOf helper.
+type Coded interface {
+	error
+
+	// Code returns the diagnostic code associated with the error.
+	Code() string
+}
+
+// codedError is an implementation of Coded that wraps another error.
+type codedError struct {
+	code string // The diagnostic code
+	err  error  // The wrapped error
+}
+
+// Error returns the error message.
+func (ce *codedError) Error() string {
+	return ce.err.Error()
+}
+
+// Code returns the diagnostic code associated with the error.
+func (ce *codedError) Code() string {
+	return ce.code
+}
+
+// Unwrap returns the wrapped error.
+func (ce *codedError) Unwrap() error {
+	return ce.err
+}
+
+// WithCode attaches a diagnostic code to an error.  The error message
+// is not altered.
+func WithCode(err error, code string) error {
+	return &codedError{
+		code: code,
+		err:  err,
+	}
+}
+
+// CodeOf retrieves the diagnostic code associated with an error.  It
+// returns the code of the first error in the chain that implements
+// Coded, along with a boolean true; if no code is available, it
+// returns an empty string and false.
+func CodeOf(err error) (string, bool) {
+	var ce Coded
+	if errors.As(err, &ce) {
+		return ce.Code(), true
+	}
+
+	return "", false
+}
+
+// CodeInfo describes a diagnostic code registered with a Registry.
+type CodeInfo struct {
+	Code        string   // The diagnostic code, e.g., "CFG1003"
+	Title       string   // A short, one-line title
+	Severity    Severity // The default severity
+	Explanation string   // A long explanation of the diagnostic
+	HelpURL     string   // A URL with more information, if any
+}
+
+// Registry is a registry of diagnostic codes.  Applications register
+// the codes they use, along with descriptive information, which may
+// then be looked up to explain a diagnostic.
+type Registry struct {
+	sync.RWMutex
+
+	codes map[string]CodeInfo // Registered codes
+}
+
+// NewRegistry constructs a new, empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		codes: map[string]CodeInfo{},
+	}
+}
+
+// Register registers one or more diagnostic codes with the registry.
+// It is an error to register a code more than once; if any code
+// cannot be registered, none of the codes are registered.
+func (r *Registry) Register(infos ...CodeInfo) error {
+	// Lock the mutex for thread safety
+	r.Lock()
+	defer r.Unlock()
+
+	// Check the codes first
+	seen := map[string]bool{}
+	for _, info := range infos {
+		if info.Code == "" {
+			return ErrEmptyCode
+		}
+		if _, ok := r.codes[info.Code]; ok || seen[info.Code] {
+			return fmt.Errorf("%w: %s", ErrDuplicateCode, info.Code)
+		}
+		seen[info.Code] = true
+	}
+
+	// Register them
+	for _, info := range infos {
+		r.codes[info.Code] = info
+	}
+
+	return nil
+}
+
+// Lookup looks up a diagnostic code.  It returns the information
+// for the code and a boolean true if the code is registered, or a
+// zero CodeInfo and false otherwise.
+func (r *Registry) Lookup(code string) (CodeInfo, bool) {
+	// Lock the mutex for thread safety
+	r.RLock()
+	defer r.RUnlock()
+
+	info, ok := r.codes[code]
+
+	return info, ok
+}
+
+// Codes returns a sorted list of all the registered diagnostic codes.
+func (r *Registry) Codes() []string {
+	// Lock the mutex for thread safety
+	r.RLock()
+	defer r.RUnlock()
+
+	codes := make([]string, 0, len(r.codes))
+	for code := range r.codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes
+}
+
+// Explain returns the long explanation of a diagnostic code,
+// suitable for implementing an "explain" subcommand.  The text
+// begins with the code and its title, followed by the explanation
+// and the help URL, if any.  If the code is not registered, an
+// error wrapping ErrUnknownCode is returned.
+func (r *Registry) Explain(code string) (string, error) {
+	info, ok := r.Lookup(code)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownCode, code)
+	}
+
+	paras := []string{code}
+	if info.Title != "" {
+		paras[0] += ": " + info.Title
+	}
+	if info.Explanation != "" {
+		paras = append(paras, strings.TrimRight(info.Explanation, "\n"))
+	}
+	if info.HelpURL != "" {
+		paras = append(paras, "For more information, see: "+info.HelpURL)
+	}
+
+	return strings.Join(paras, "\n\n") + "\n", nil
+}
+
+// New constructs a new error with the specified diagnostic code,
+// using the default severity registered for the code.  The message
+// is constructed as for fmt.Errorf.  If the code is not registered,
+// the error will have SeverityError.
+func (r *Registry) New(code, format string, args ...interface{}) error {
+	sev := SeverityError
+	if info, ok := r.Lookup(code); ok {
+		sev = info.Severity
+	}
+
+	return WithCode(Severityf(sev, format, args...), code)
+}
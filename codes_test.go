@@ -0,0 +1,252 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodedErrorImplementsCoded(t *testing.T) {
+	assert.Implements(t, (*Coded)(nil), &codedError{})
+}
+
+func TestCodedErrorError(t *testing.T) {
+	obj := &codedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestCodedErrorCode(t *testing.T) {
+	obj := &codedError{
+		code: "CFG1003",
+	}
+
+	result := obj.Code()
+
+	assert.Equal(t, "CFG1003", result)
+}
+
+func TestCodedErrorUnwrap(t *testing.T) {
+	obj := &codedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestWithCode(t *testing.T) {
+	result := WithCode(assert.AnError, "CFG1003")
+
+	assert.Equal(t, &codedError{
+		code: "CFG1003",
+		err:  assert.AnError,
+	}, result)
+}
+
+func TestCodeOfWrapped(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", WithCode(assert.AnError, "CFG1003"))
+
+	result, ok := CodeOf(err)
+
+	assert.True(t, ok)
+	assert.Equal(t, "CFG1003", result)
+}
+
+func TestCodeOfMissing(t *testing.T) {
+	result, ok := CodeOf(assert.AnError)
+
+	assert.False(t, ok)
+	assert.Equal(t, "", result)
+}
+
+func TestNewRegistry(t *testing.T) {
+	result := NewRegistry()
+
+	assert.Equal(t, &Registry{
+		codes: map[string]CodeInfo{},
+	}, result)
+}
+
+func TestRegistryRegisterBase(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{},
+	}
+
+	err := obj.Register(CodeInfo{Code: "CFG1001"}, CodeInfo{Code: "CFG1002"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]CodeInfo{
+		"CFG1001": {Code: "CFG1001"},
+		"CFG1002": {Code: "CFG1002"},
+	}, obj.codes)
+}
+
+func TestRegistryRegisterEmpty(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{},
+	}
+
+	err := obj.Register(CodeInfo{Code: "CFG1001"}, CodeInfo{})
+
+	assert.Same(t, ErrEmptyCode, err)
+	assert.Equal(t, map[string]CodeInfo{}, obj.codes)
+}
+
+func TestRegistryRegisterDuplicate(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{
+			"CFG1001": {Code: "CFG1001"},
+		},
+	}
+
+	err := obj.Register(CodeInfo{Code: "CFG1002"}, CodeInfo{Code: "CFG1001"})
+
+	assert.True(t, errors.Is(err, ErrDuplicateCode))
+	assert.EqualError(t, err, "diagnostic code already registered: CFG1001")
+	assert.Equal(t, map[string]CodeInfo{
+		"CFG1001": {Code: "CFG1001"},
+	}, obj.codes)
+}
+
+func TestRegistryRegisterDuplicateArgs(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{},
+	}
+
+	err := obj.Register(CodeInfo{Code: "CFG1001"}, CodeInfo{Code: "CFG1001"})
+
+	assert.True(t, errors.Is(err, ErrDuplicateCode))
+	assert.Equal(t, map[string]CodeInfo{}, obj.codes)
+}
+
+func TestRegistryLookupFound(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{
+			"CFG1001": {Code: "CFG1001", Title: "title"},
+		},
+	}
+
+	result, ok := obj.Lookup("CFG1001")
+
+	assert.True(t, ok)
+	assert.Equal(t, CodeInfo{Code: "CFG1001", Title: "title"}, result)
+}
+
+func TestRegistryLookupMissing(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{},
+	}
+
+	result, ok := obj.Lookup("CFG1001")
+
+	assert.False(t, ok)
+	assert.Equal(t, CodeInfo{}, result)
+}
+
+func TestRegistryCodes(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{
+			"CFG1002": {Code: "CFG1002"},
+			"CFG1003": {Code: "CFG1003"},
+			"CFG1001": {Code: "CFG1001"},
+		},
+	}
+
+	result := obj.Codes()
+
+	assert.Equal(t, []string{"CFG1001", "CFG1002", "CFG1003"}, result)
+}
+
+func TestRegistryExplainFull(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{
+			"CFG1003": {
+				Code:        "CFG1003",
+				Title:       "Duplicate key",
+				Explanation: "A key was defined twice.\n",
+				HelpURL:     "https://example.com/CFG1003",
+			},
+		},
+	}
+
+	result, err := obj.Explain("CFG1003")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CFG1003: Duplicate key\n\nA key was defined twice.\n\nFor more information, see: https://example.com/CFG1003\n", result)
+}
+
+func TestRegistryExplainMinimal(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{
+			"CFG1003": {Code: "CFG1003"},
+		},
+	}
+
+	result, err := obj.Explain("CFG1003")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CFG1003\n", result)
+}
+
+func TestRegistryExplainUnknown(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{},
+	}
+
+	result, err := obj.Explain("CFG1003")
+
+	assert.True(t, errors.Is(err, ErrUnknownCode))
+	assert.Equal(t, "", result)
+}
+
+func TestRegistryNewRegistered(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{
+			"CFG1003": {Code: "CFG1003", Severity: SeverityWarning},
+		},
+	}
+
+	result := obj.New("CFG1003", "key %q defined twice", "foo")
+
+	assert.Equal(t, `key "foo" defined twice`, result.Error())
+	assert.True(t, IsWarning(result))
+	code, ok := CodeOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, "CFG1003", code)
+}
+
+func TestRegistryNewUnregistered(t *testing.T) {
+	obj := &Registry{
+		codes: map[string]CodeInfo{},
+	}
+
+	result := obj.New("CFG1003", "key %q defined twice", "foo")
+
+	assert.Equal(t, `key "foo" defined twice`, result.Error())
+	assert.Equal(t, SeverityError, SeverityOf(result))
+	code, ok := CodeOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, "CFG1003", code)
+}
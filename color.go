@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"io"
+	"os"
+)
+
+// isTerminal is a patch point to allow terminal detection to be
+// tested.
+var isTerminal = fileIsTerminal
+
+// ColorMode describes when the default format should colorize its
+// output using ANSI escape sequences.
+type ColorMode int
+
+// Recognized color modes.
+const (
+	ColorNever  ColorMode = iota // Never colorize the output
+	ColorAuto                    // Colorize output to a terminal
+	ColorAlways                  // Always colorize the output
+)
+
+// ColorTheme describes the colors used to style the default format.
+// Each color is a sequence of ANSI SGR parameters, such as "1;31" for
+// bold red; an empty string leaves the corresponding text unstyled.
+type ColorTheme struct {
+	Labels   [numSeverities]string // Colors of the severity labels
+	Code     string                // Color of the diagnostic code
+	Position string                // Color of the position
+	Scope    string                // Color of the scope path
+}
+
+// DefaultColorTheme is the ColorTheme used if none is specified with
+// FormatColorTheme.
+var DefaultColorTheme = ColorTheme{
+	Labels: [numSeverities]string{
+		SeverityDebug:   "90",
+		SeverityInfo:    "32",
+		SeverityNotice:  "36",
+		SeverityWarning: "1;33",
+		SeverityError:   "1;31",
+		SeverityFatal:   "1;35",
+	},
+	Code:     "1",
+	Position: "1",
+	Scope:    "34",
+}
+
+// fileIsTerminal determines if the output stream is a terminal.  It
+// recognizes streams, such as *os.File, that can describe themselves
+// with a Stat method; a stream is considered a terminal if it is a
+// character device.
+func fileIsTerminal(out io.Writer) bool {
+	file, ok := out.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return false
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// FormatColor specifies when the default format should colorize the
+// severity label, diagnostic code, position, and scope path of the
+// error using ANSI escape sequences.  With ColorAuto, the output is
+// colorized if the NO_COLOR environment variable is empty and either
+// the FORCE_COLOR environment variable is set to a value other than
+// "0" or the output stream of the reporter is a terminal.  The
+// default is ColorNever.  This option does not alter formats set by
+// other options, such as FormatError.
+func FormatColor(mode ColorMode) FormatOption {
+	return func(f *Formatters) {
+		f.color = mode
+	}
+}
+
+// FormatColorTheme specifies the colors to use when the default
+// format is colorized; see FormatColor.  The default is
+// DefaultColorTheme.
+func FormatColorTheme(theme ColorTheme) FormatOption {
+	return func(f *Formatters) {
+		f.theme = theme
+	}
+}
+
+// detectColor determines whether output to the specified stream
+// should be colorized when the color mode is ColorAuto.  It is called
+// by reporters constructing a Formatters for an output stream.
+func (f *Formatters) detectColor(out io.Writer) {
+	if f.color != ColorAuto {
+		return
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		f.colorOut = false
+	} else if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" {
+		f.colorOut = true
+	} else {
+		f.colorOut = isTerminal(out)
+	}
+}
+
+// colorize styles text with the specified color, if colorization is
+// enabled.
+func (f *Formatters) colorize(color, text string) string {
+	if color == "" || (f.color != ColorAlways && (f.color != ColorAuto || !f.colorOut)) {
+		return text
+	}
+
+	return "\x1b[" + color + "m" + text + "\x1b[0m"
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klmitch/patcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIsTerminalNoStat(t *testing.T) {
+	result := fileIsTerminal(&bytes.Buffer{})
+
+	assert.False(t, result)
+}
+
+func TestFileIsTerminalRegularFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	result := fileIsTerminal(f)
+
+	assert.False(t, result)
+}
+
+func TestFileIsTerminalStatError(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	require.NoError(t, err)
+	f.Close()
+
+	result := fileIsTerminal(f)
+
+	assert.False(t, result)
+}
+
+func TestFormatColor(t *testing.T) {
+	obj := &Formatters{}
+
+	opt := FormatColor(ColorAlways)
+	opt(obj)
+
+	assert.Equal(t, ColorAlways, obj.color)
+}
+
+func TestFormatColorTheme(t *testing.T) {
+	theme := ColorTheme{Code: "4"}
+	obj := &Formatters{}
+
+	opt := FormatColorTheme(theme)
+	opt(obj)
+
+	assert.Equal(t, theme, obj.theme)
+}
+
+func TestFormattersDetectColorNever(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+	obj := &Formatters{}
+
+	obj.detectColor(&bytes.Buffer{})
+
+	assert.False(t, obj.colorOut)
+}
+
+func TestFormattersDetectColorTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	out := &bytes.Buffer{}
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		assert.Same(t, out, w)
+		return true
+	}).Install().Restore()
+	obj := &Formatters{
+		color: ColorAuto,
+	}
+
+	obj.detectColor(out)
+
+	assert.True(t, obj.colorOut)
+}
+
+func TestFormattersDetectColorNotTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		return false
+	}).Install().Restore()
+	obj := &Formatters{
+		color:    ColorAuto,
+		colorOut: true,
+	}
+
+	obj.detectColor(&bytes.Buffer{})
+
+	assert.False(t, obj.colorOut)
+}
+
+func TestFormattersDetectColorNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("FORCE_COLOR", "1")
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		return true
+	}).Install().Restore()
+	obj := &Formatters{
+		color: ColorAuto,
+	}
+
+	obj.detectColor(&bytes.Buffer{})
+
+	assert.False(t, obj.colorOut)
+}
+
+func TestFormattersDetectColorForceColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		return false
+	}).Install().Restore()
+	obj := &Formatters{
+		color: ColorAuto,
+	}
+
+	obj.detectColor(&bytes.Buffer{})
+
+	assert.True(t, obj.colorOut)
+}
+
+func TestFormattersDetectColorForceColorZero(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "0")
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		return false
+	}).Install().Restore()
+	obj := &Formatters{
+		color: ColorAuto,
+	}
+
+	obj.detectColor(&bytes.Buffer{})
+
+	assert.False(t, obj.colorOut)
+}
+
+func TestFormattersColorizeNever(t *testing.T) {
+	obj := &Formatters{
+		colorOut: true,
+	}
+
+	result := obj.colorize("1", "text")
+
+	assert.Equal(t, "text", result)
+}
+
+func TestFormattersColorizeAutoOff(t *testing.T) {
+	obj := &Formatters{
+		color: ColorAuto,
+	}
+
+	result := obj.colorize("1", "text")
+
+	assert.Equal(t, "text", result)
+}
+
+func TestFormattersColorizeAutoOn(t *testing.T) {
+	obj := &Formatters{
+		color:    ColorAuto,
+		colorOut: true,
+	}
+
+	result := obj.colorize("1", "text")
+
+	assert.Equal(t, "\x1b[1mtext\x1b[0m", result)
+}
+
+func TestFormattersColorizeAlways(t *testing.T) {
+	obj := &Formatters{
+		color: ColorAlways,
+	}
+
+	result := obj.colorize("1;31", "text")
+
+	assert.Equal(t, "\x1b[1;31mtext\x1b[0m", result)
+}
+
+func TestFormattersColorizeNoColor(t *testing.T) {
+	obj := &Formatters{
+		color: ColorAlways,
+	}
+
+	result := obj.colorize("", "text")
+
+	assert.Equal(t, "text", result)
+}
+
+func TestFormattersFormatDefaultColor(t *testing.T) {
+	obj := NewFormatters(FormatColor(ColorAlways), FormatCodes(true))
+	err := WithCode(WithScope(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"), "deploy"), "CFG1003")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "\x1b[1mfile.cfg:3\x1b[0m: \x1b[34mdeploy\x1b[0m: \x1b[1;31mERROR\x1b[0m[\x1b[1mCFG1003\x1b[0m]: test error", result)
+}
+
+func TestFormattersFormatDefaultColorAutoNotTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	obj := NewFormatters(FormatColor(ColorAuto), FormatCodes(true))
+	obj.detectColor(&bytes.Buffer{})
+	err := WithCode(WithScope(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"), "deploy"), "CFG1003")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "file.cfg:3: deploy: ERROR[CFG1003]: test error", result)
+}
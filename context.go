@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import "context"
+
+// reporterKey is the type of the context key used to store a
+// Reporter in a context.Context.
+type reporterKey struct{}
+
+// WithReporter returns a copy of the parent context that carries the
+// specified Reporter.  The Reporter may be retrieved using
+// FromContext.
+func WithReporter(ctx context.Context, rep Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, rep)
+}
+
+// FromContext retrieves the Reporter carried by the context.  If the
+// context does not carry a Reporter, the root reporter returned by
+// Root is returned.
+func FromContext(ctx context.Context) Reporter {
+	if rep, ok := ctx.Value(reporterKey{}).(Reporter); ok && rep != nil {
+		return rep
+	}
+
+	return Root()
+}
+
+// Report is a helper that reports an error to the Reporter carried by
+// the context, as returned by FromContext.
+func Report(ctx context.Context, err error) {
+	FromContext(ctx).Report(err)
+}
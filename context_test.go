@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithReporter(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := WithReporter(context.Background(), rep)
+
+	assert.Same(t, rep, result.Value(reporterKey{}))
+}
+
+func TestFromContextBase(t *testing.T) {
+	rep := &MockReporter{}
+	ctx := context.WithValue(context.Background(), reporterKey{}, rep)
+
+	result := FromContext(ctx)
+
+	assert.Same(t, rep, result)
+}
+
+func TestFromContextMissing(t *testing.T) {
+	result := FromContext(context.Background())
+
+	assert.Same(t, root, result)
+}
+
+func TestFromContextNil(t *testing.T) {
+	ctx := context.WithValue(context.Background(), reporterKey{}, Reporter(nil))
+
+	result := FromContext(ctx)
+
+	assert.Same(t, root, result)
+}
+
+func TestReport(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError)
+	ctx := context.WithValue(context.Background(), reporterKey{}, rep)
+
+	Report(ctx, assert.AnError)
+
+	rep.AssertExpectations(t)
+}
@@ -17,17 +17,18 @@ package kent
 import "sync/atomic"
 
 // CountingReporter is a Reporter that counts the number of errors and
-// warnings that are reported using it.
+// warnings that are reported using it.  Counts are also maintained
+// for each severity.
 type CountingReporter struct {
-	errors   int64    // Number of errors
-	warnings int64    // Number of warnings
-	rep      Reporter // Child reporter
+	counts [numSeverities]int64 // Counts by severity
+	rep    Reporter             // Child reporter
 }
 
 // NewCountingReporter constructs a new counting Reporter.  A counting
 // reporter counts the number of errors and warnings that are reported
 // using it, and makes those counts available through the Errors and
-// Warnings methods.
+// Warnings methods.  The counts for a specific severity are available
+// through the Count method.
 func NewCountingReporter(rep Reporter) *CountingReporter {
 	return &CountingReporter{
 		rep: rep,
@@ -39,11 +40,7 @@ func NewCountingReporter(rep Reporter) *CountingReporter {
 // implementation uses, and passes on the error to the wrapped
 // Reporter.
 func (cr *CountingReporter) Report(err error) {
-	if IsWarning(err) {
-		atomic.AddInt64(&cr.warnings, 1)
-	} else {
-		atomic.AddInt64(&cr.errors, 1)
-	}
+	atomic.AddInt64(&cr.counts[SeverityOf(err)], 1)
 
 	cr.rep.Report(err)
 }
@@ -54,18 +51,27 @@ func (cr *CountingReporter) Unwrap() []Reporter {
 	return []Reporter{cr.rep}
 }
 
-// Errors returns the number of errors counted so far by the counting
-// reporter.
-func (cr *CountingReporter) Errors() int {
-	count := atomic.LoadInt64(&cr.errors)
+// Count returns the number of errors with the specified severity
+// counted so far by the counting reporter.
+func (cr *CountingReporter) Count(sev Severity) int {
+	if !sev.valid() {
+		return 0
+	}
+
+	count := atomic.LoadInt64(&cr.counts[sev])
 
 	return int(count)
 }
 
+// Errors returns the number of errors counted so far by the counting
+// reporter.  This includes both errors with SeverityError and errors
+// with SeverityFatal.
+func (cr *CountingReporter) Errors() int {
+	return cr.Count(SeverityError) + cr.Count(SeverityFatal)
+}
+
 // Warnings returns the number of warnings counted so far by the
 // counting reporter.
 func (cr *CountingReporter) Warnings() int {
-	count := atomic.LoadInt64(&cr.warnings)
-
-	return int(count)
+	return cr.Count(SeverityWarning)
 }
@@ -44,7 +44,7 @@ func TestCountingReporterReportError(t *testing.T) {
 	obj.Report(assert.AnError)
 
 	assert.Equal(t, &CountingReporter{
-		errors: int64(1),
+		counts: [numSeverities]int64{SeverityError: 1},
 		rep:    rep,
 	}, obj)
 	rep.AssertExpectations(t)
@@ -61,8 +61,8 @@ func TestCountingReporterReportWarning(t *testing.T) {
 	obj.Report(err)
 
 	assert.Equal(t, &CountingReporter{
-		warnings: int64(1),
-		rep:      rep,
+		counts: [numSeverities]int64{SeverityWarning: 1},
+		rep:    rep,
 	}, obj)
 	rep.AssertExpectations(t)
 }
@@ -78,9 +78,46 @@ func TestCountingReporterUnwrap(t *testing.T) {
 	assert.Equal(t, []Reporter{rep}, result)
 }
 
+func TestCountingReporterReportSeverity(t *testing.T) {
+	err := NewSeverity(SeverityNotice, "a notice")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	obj := &CountingReporter{
+		rep: rep,
+	}
+
+	obj.Report(err)
+
+	assert.Equal(t, &CountingReporter{
+		counts: [numSeverities]int64{SeverityNotice: 1},
+		rep:    rep,
+	}, obj)
+	rep.AssertExpectations(t)
+}
+
+func TestCountingReporterCount(t *testing.T) {
+	obj := &CountingReporter{
+		counts: [numSeverities]int64{SeverityInfo: 42},
+	}
+
+	result := obj.Count(SeverityInfo)
+
+	assert.Equal(t, 42, result)
+}
+
+func TestCountingReporterCountInvalid(t *testing.T) {
+	obj := &CountingReporter{
+		counts: [numSeverities]int64{SeverityFatal: 42},
+	}
+
+	result := obj.Count(SeverityFatal + 1)
+
+	assert.Equal(t, 0, result)
+}
+
 func TestCountingReporterErrors(t *testing.T) {
 	obj := &CountingReporter{
-		errors: int64(42),
+		counts: [numSeverities]int64{SeverityError: 40, SeverityFatal: 2},
 	}
 
 	result := obj.Errors()
@@ -90,7 +127,7 @@ func TestCountingReporterErrors(t *testing.T) {
 
 func TestCountingReporterWarnings(t *testing.T) {
 	obj := &CountingReporter{
-		warnings: int64(42),
+		counts: [numSeverities]int64{SeverityWarning: 42, SeverityNotice: 5},
 	}
 
 	result := obj.Warnings()
@@ -0,0 +1,253 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// timeNow is a patch point to allow the time-dependent Reporter
+// instances to be tested.
+var timeNow = time.Now
+
+// DedupKeyFunc describes a function that determines the identity of
+// an error for a DedupReporter.  Errors with the same key are
+// considered duplicates.
+type DedupKeyFunc func(err error) string
+
+// DedupKeyDefault is the default DedupKeyFunc.  It identifies errors
+// by their severity, their message, and their position, if they have
+// one.
+func DedupKeyDefault(err error) string {
+	key := fmt.Sprintf("%s\x00%s", SeverityOf(err), err)
+	if pos, ok := PositionOf(err); ok && pos.known() {
+		key += "\x00" + pos.String()
+	}
+
+	return key
+}
+
+// repeatedError wraps an error that was reported repeatedly; it is
+// reported by DedupReporter when it is closed.
+type repeatedError struct {
+	count int   // The number of repetitions suppressed
+	err   error // The wrapped error
+}
+
+// Error returns the error message.
+func (re *repeatedError) Error() string {
+	if re.count == 1 {
+		return fmt.Sprintf("%s (repeated 1 time)", re.err)
+	}
+
+	return fmt.Sprintf("%s (repeated %d times)", re.err, re.count)
+}
+
+// Unwrap returns the wrapped error.
+func (re *repeatedError) Unwrap() error {
+	return re.err
+}
+
+// dedupEntry records an error seen by a DedupReporter.
+type dedupEntry struct {
+	err      error     // The first error reported with the key
+	last     time.Time // When the error was last passed on
+	repeated int       // The number of repetitions suppressed
+}
+
+// DedupReporter is a Reporter that suppresses duplicate errors,
+// passing on only the first error with a particular identity, either
+// forever or within a time window.
+type DedupReporter struct {
+	sync.Mutex
+
+	key     DedupKeyFunc           // Function for the identity
+	window  time.Duration          // Window for duplicates; 0 = forever
+	summary bool                   // Report repetitions when closed
+	seen    map[string]*dedupEntry // Entries by key
+	order   []string               // Keys in the order first seen
+	pruned  time.Time              // When expired entries were pruned
+	closed  bool                   // Set once the reporter is closed
+	rep     Reporter               // Child reporter
+}
+
+// DedupReporterOption describes an option for a DedupReporter.
+type DedupReporterOption func(*DedupReporter)
+
+// DedupKey specifies the function used to determine the identity of
+// an error.  The default is DedupKeyDefault.
+func DedupKey(key DedupKeyFunc) DedupReporterOption {
+	return func(dr *DedupReporter) {
+		dr.key = key
+	}
+}
+
+// DedupWindow specifies a time window for duplicates.  An error is
+// suppressed only if an error with the same identity was passed on
+// within the window; otherwise, it is passed on, and the window
+// begins again.  By default, duplicates are suppressed forever.  With
+// a window, the reporter periodically forgets errors whose window has
+// elapsed and that were never suppressed, so its memory use is
+// bounded by the number of distinct errors seen within a window,
+// plus the number of distinct errors that were suppressed.
+func DedupWindow(window time.Duration) DedupReporterOption {
+	return func(dr *DedupReporter) {
+		dr.window = window
+	}
+}
+
+// DedupSummary specifies that, when the reporter is closed, each
+// error that was suppressed at least once should be reported again,
+// with " (repeated N times)" appended to its message.  The summary
+// error wraps the first error reported with the identity, so its
+// severity, position, and other information are preserved.
+func DedupSummary() DedupReporterOption {
+	return func(dr *DedupReporter) {
+		dr.summary = true
+	}
+}
+
+// NewDedupReporter constructs a new de-duplicating reporter.  A
+// de-duplicating reporter passes on only the first of the reported
+// errors with a particular identity, as determined by the DedupKey
+// option, suppressing the rest; if the DedupWindow option is passed,
+// an error is again passed on once the window has elapsed.  If the
+// DedupSummary option is passed, the number of suppressed repetitions
+// is reported when the reporter is closed, such as by the Close
+// helper.
+func NewDedupReporter(rep Reporter, options ...DedupReporterOption) *DedupReporter {
+	obj := &DedupReporter{
+		key:  DedupKeyDefault,
+		seen: map[string]*dedupEntry{},
+		rep:  rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (dr *DedupReporter) Report(err error) {
+	key := dr.key(err)
+	now := timeNow()
+
+	// Determine whether the error is a duplicate
+	dup := false
+	dr.Lock()
+	dr.prune(now)
+	entry, ok := dr.seen[key]
+	switch {
+	case !ok:
+		dr.seen[key] = &dedupEntry{err: err, last: now}
+		dr.order = append(dr.order, key)
+
+	case dr.window > 0 && now.Sub(entry.last) >= dr.window:
+		entry.last = now
+
+	default:
+		entry.repeated++
+		dup = true
+	}
+	dr.Unlock()
+
+	if !dup {
+		dr.rep.Report(err)
+	}
+}
+
+// prune is a helper that forgets the errors whose window has elapsed
+// and that were never suppressed, so that they do not accumulate.
+// Errors that were suppressed are retained for the summary.  Pruning
+// is performed at most once per window.  It must be called with the
+// mutex held.
+func (dr *DedupReporter) prune(now time.Time) {
+	if dr.window <= 0 || now.Sub(dr.pruned) < dr.window {
+		return
+	}
+	dr.pruned = now
+
+	order := dr.order[:0]
+	for _, key := range dr.order {
+		if entry := dr.seen[key]; entry.repeated == 0 && now.Sub(entry.last) >= dr.window {
+			delete(dr.seen, key)
+			continue
+		}
+		order = append(order, key)
+	}
+	dr.order = order
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (dr *DedupReporter) Unwrap() []Reporter {
+	return []Reporter{dr.rep}
+}
+
+// Suppressed returns the number of errors suppressed so far by the
+// de-duplicating reporter.
+func (dr *DedupReporter) Suppressed() int {
+	// Lock the mutex for thread safety
+	dr.Lock()
+	defer dr.Unlock()
+
+	total := 0
+	for _, entry := range dr.seen {
+		total += entry.repeated
+	}
+
+	return total
+}
+
+// Close reports the number of suppressed repetitions of each error, if
+// the DedupSummary option was passed.  The repetitions are reported
+// in the order the errors were first seen, and only the first call
+// to Close reports them.
+func (dr *DedupReporter) Close() error {
+	// Lock the mutex for thread safety
+	dr.Lock()
+	if dr.closed || !dr.summary {
+		dr.closed = true
+		dr.Unlock()
+		return nil
+	}
+	dr.closed = true
+
+	// Collect the repetitions
+	errs := []error{}
+	for _, key := range dr.order {
+		if entry := dr.seen[key]; entry.repeated > 0 {
+			errs = append(errs, &repeatedError{
+				count: entry.repeated,
+				err:   entry.err,
+			})
+		}
+	}
+	dr.Unlock()
+
+	for _, err := range errs {
+		dr.rep.Report(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klmitch/patcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDedupKeyDefaultBase(t *testing.T) {
+	result := DedupKeyDefault(assert.AnError)
+
+	assert.Equal(t, "error\x00"+assert.AnError.Error(), result)
+}
+
+func TestDedupKeyDefaultPosition(t *testing.T) {
+	result := DedupKeyDefault(WarningAt(Position{File: "file", Line: 3, Column: 5}, "a warning"))
+
+	assert.Equal(t, "warning\x00a warning\x00file:3:5", result)
+}
+
+func TestDedupKeyDefaultDistinct(t *testing.T) {
+	assert.NotEqual(t, DedupKeyDefault(errors.New("a problem")), DedupKeyDefault(NewWarning("a problem")))
+	assert.NotEqual(t, DedupKeyDefault(ErrorAt(Position{File: "a"}, "a problem")), DedupKeyDefault(ErrorAt(Position{File: "b"}, "a problem")))
+}
+
+func TestRepeatedErrorImplementsError(t *testing.T) {
+	assert.Implements(t, (*error)(nil), &repeatedError{})
+}
+
+func TestRepeatedErrorErrorOnce(t *testing.T) {
+	obj := &repeatedError{
+		count: 1,
+		err:   assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error()+" (repeated 1 time)", result)
+}
+
+func TestRepeatedErrorErrorMany(t *testing.T) {
+	obj := &repeatedError{
+		count: 3,
+		err:   assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error()+" (repeated 3 times)", result)
+}
+
+func TestRepeatedErrorUnwrap(t *testing.T) {
+	obj := &repeatedError{
+		count: 3,
+		err:   assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestDedupReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &DedupReporter{})
+}
+
+func TestDedupKey(t *testing.T) {
+	obj := &DedupReporter{}
+
+	opt := DedupKey(func(err error) string {
+		return "key"
+	})
+	opt(obj)
+
+	assert.Equal(t, "key", obj.key(assert.AnError))
+}
+
+func TestDedupWindow(t *testing.T) {
+	obj := &DedupReporter{}
+
+	opt := DedupWindow(time.Minute)
+	opt(obj)
+
+	assert.Equal(t, time.Minute, obj.window)
+}
+
+func TestDedupSummary(t *testing.T) {
+	obj := &DedupReporter{}
+
+	opt := DedupSummary()
+	opt(obj)
+
+	assert.True(t, obj.summary)
+}
+
+func TestNewDedupReporterBase(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewDedupReporter(rep)
+
+	assert.NotNil(t, result.key)
+	assert.Equal(t, DedupKeyDefault(assert.AnError), result.key(assert.AnError))
+	result.key = nil
+	assert.Equal(t, &DedupReporter{
+		seen: map[string]*dedupEntry{},
+		rep:  rep,
+	}, result)
+}
+
+func TestNewDedupReporterOptions(t *testing.T) {
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *DedupReporter
+	options := []DedupReporterOption{
+		func(dr *DedupReporter) {
+			opt1Called = dr
+		},
+		func(dr *DedupReporter) {
+			opt2Called = dr
+		},
+	}
+
+	result := NewDedupReporter(rep, options...)
+
+	assert.Same(t, rep, result.rep)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestDedupReporterReportForever(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer patcher.SetVar(&timeNow, func() time.Time {
+		now = now.Add(time.Hour)
+		return now
+	}).Install().Restore()
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	rep := &MockReporter{}
+	rep.On("Report", err1).Once()
+	rep.On("Report", err2).Once()
+	obj := NewDedupReporter(rep)
+
+	obj.Report(err1)
+	obj.Report(err2)
+	obj.Report(errors.New("error 1"))
+	obj.Report(err1)
+
+	assert.Equal(t, []string{DedupKeyDefault(err1), DedupKeyDefault(err2)}, obj.order)
+	assert.Equal(t, 2, obj.seen[DedupKeyDefault(err1)].repeated)
+	assert.Equal(t, 0, obj.seen[DedupKeyDefault(err2)].repeated)
+	rep.AssertExpectations(t)
+}
+
+func TestDedupReporterReportWindow(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer patcher.SetVar(&timeNow, func() time.Time {
+		return now
+	}).Install().Restore()
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Twice()
+	obj := NewDedupReporter(rep, DedupWindow(time.Minute))
+
+	obj.Report(assert.AnError)
+	now = now.Add(30 * time.Second)
+	obj.Report(assert.AnError)
+	now = now.Add(30 * time.Second)
+	obj.Report(assert.AnError)
+	now = now.Add(59 * time.Second)
+	obj.Report(assert.AnError)
+
+	entry := obj.seen[DedupKeyDefault(assert.AnError)]
+	assert.Equal(t, 2, entry.repeated)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 1, 0, 0, time.UTC), entry.last)
+	rep.AssertExpectations(t)
+}
+
+func TestDedupReporterReportPrune(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer patcher.SetVar(&timeNow, func() time.Time {
+		return now
+	}).Install().Restore()
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+	rep := &MockReporter{}
+	rep.On("Report", err1).Once()
+	rep.On("Report", err2).Once()
+	rep.On("Report", err3).Once()
+	obj := NewDedupReporter(rep, DedupWindow(time.Minute))
+
+	obj.Report(err1)
+	obj.Report(err2)
+	obj.Report(err2)
+	now = now.Add(2 * time.Minute)
+	obj.Report(err3)
+
+	assert.Equal(t, []string{DedupKeyDefault(err2), DedupKeyDefault(err3)}, obj.order)
+	assert.Len(t, obj.seen, 2)
+	assert.Equal(t, 1, obj.seen[DedupKeyDefault(err2)].repeated)
+	assert.Equal(t, now, obj.pruned)
+	rep.AssertExpectations(t)
+}
+
+func TestDedupReporterPruneOncePerWindow(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	obj := NewDedupReporter(Root(), DedupWindow(time.Minute))
+	obj.seen["key1"] = &dedupEntry{err: assert.AnError, last: now}
+	obj.seen["key2"] = &dedupEntry{err: assert.AnError, last: now.Add(50 * time.Second)}
+	obj.order = []string{"key1", "key2"}
+	obj.pruned = now
+
+	obj.prune(now.Add(70 * time.Second))
+	assert.Equal(t, []string{"key2"}, obj.order)
+	obj.prune(now.Add(115 * time.Second))
+	assert.Equal(t, []string{"key2"}, obj.order)
+	obj.prune(now.Add(130 * time.Second))
+
+	assert.Equal(t, []string{}, obj.order)
+	assert.Len(t, obj.seen, 0)
+}
+
+func TestDedupReporterPruneForever(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	obj := NewDedupReporter(Root())
+	obj.seen["key"] = &dedupEntry{err: assert.AnError}
+	obj.order = []string{"key"}
+
+	obj.prune(now)
+
+	assert.Len(t, obj.seen, 1)
+	assert.Equal(t, []string{"key"}, obj.order)
+}
+
+func TestDedupReporterReportKey(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	rep := &MockReporter{}
+	rep.On("Report", err1).Once()
+	obj := NewDedupReporter(rep, DedupKey(func(err error) string {
+		return "key"
+	}))
+
+	obj.Report(err1)
+	obj.Report(err2)
+
+	assert.Equal(t, 1, obj.Suppressed())
+	rep.AssertExpectations(t)
+}
+
+func TestDedupReporterReportConcurrent(t *testing.T) {
+	counter := NewCountingReporter(Root())
+	obj := NewDedupReporter(counter)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				obj.Report(NewWarning("a warning"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, counter.Warnings())
+	assert.Equal(t, 999, obj.Suppressed())
+}
+
+func TestDedupReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &DedupReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestDedupReporterSuppressed(t *testing.T) {
+	obj := &DedupReporter{
+		seen: map[string]*dedupEntry{
+			"a": {repeated: 2},
+			"b": {repeated: 0},
+			"c": {repeated: 40},
+		},
+	}
+
+	result := obj.Suppressed()
+
+	assert.Equal(t, 42, result)
+}
+
+func TestDedupReporterCloseNoSummary(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &DedupReporter{
+		seen: map[string]*dedupEntry{
+			"a": {err: assert.AnError, repeated: 2},
+		},
+		order: []string{"a"},
+		rep:   rep,
+	}
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.True(t, obj.closed)
+	rep.AssertExpectations(t)
+}
+
+func TestDedupReporterCloseSummary(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := NewWarning("warning 3")
+	rep := &MockReporter{}
+	rep.On("Report", &repeatedError{count: 1, err: err3}).Once()
+	rep.On("Report", &repeatedError{count: 2, err: err1}).Once()
+	obj := &DedupReporter{
+		summary: true,
+		seen: map[string]*dedupEntry{
+			"a": {err: err1, repeated: 2},
+			"b": {err: err2},
+			"c": {err: err3, repeated: 1},
+		},
+		order: []string{"c", "b", "a"},
+		rep:   rep,
+	}
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.True(t, obj.closed)
+	rep.AssertExpectations(t)
+	rep.AssertNumberOfCalls(t, "Report", 2)
+	assert.Equal(t, SeverityWarning, SeverityOf(rep.Calls[0].Arguments[0].(error)))
+	assert.Equal(t, "warning 3 (repeated 1 time)", rep.Calls[0].Arguments[0].(error).Error())
+}
+
+func TestDedupReporterCloseClosed(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &DedupReporter{
+		summary: true,
+		seen: map[string]*dedupEntry{
+			"a": {err: assert.AnError, repeated: 2},
+		},
+		order:  []string{"a"},
+		closed: true,
+		rep:    rep,
+	}
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	rep.AssertNotCalled(t, "Report", mock.Anything)
+}
+
+func TestDedupReporterCloseHelper(t *testing.T) {
+	rep := NewCapturingReporter(Root())
+	obj := NewDedupReporter(rep, DedupSummary())
+	obj.Report(WarningAt(Position{File: "file", Line: 3}, "a warning"))
+	obj.Report(WarningAt(Position{File: "file", Line: 3}, "a warning"))
+	obj.Report(WarningAt(Position{File: "file", Line: 3}, "a warning"))
+
+	err := Close(obj)
+
+	assert.NoError(t, err)
+	errs := rep.List()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "a warning (repeated 2 times)", errs[1].Error())
+	pos, ok := PositionOf(errs[1])
+	assert.True(t, ok)
+	assert.Equal(t, "file:3", pos.String())
+	assert.True(t, IsWarning(errs[1]))
+}
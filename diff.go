@@ -0,0 +1,200 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of lines of context to include around
+// changes in a unified diff.
+const diffContext = 3
+
+// Kinds of diff operations.
+const (
+	diffEqual  = ' ' // Line is unchanged
+	diffDelete = '-' // Line is deleted
+	diffInsert = '+' // Line is inserted
+)
+
+// diffOp describes a single operation in a line-based diff.
+type diffOp struct {
+	kind byte   // The kind of operation
+	line string // The line, including any line ending
+}
+
+// splitLines splits text into lines, retaining the line endings.
+func splitLines(text []byte) []string {
+	if len(text) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines computes a minimal list of operations transforming the
+// lines in a into the lines in b, using the Myers algorithm.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD == 0 {
+		return nil
+	}
+
+	// Compute the furthest-reaching paths for each edit distance,
+	// saving a trace for backtracking
+	off := maxD
+	v := make([]int, 2*maxD+2)
+	trace := [][]int{}
+outer:
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				break outer
+			}
+		}
+	}
+
+	// Backtrack through the trace to construct the operations
+	ops := []diffOp{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[off+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: diffEqual, line: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: diffInsert, line: b[y-1]})
+			} else {
+				ops = append(ops, diffOp{kind: diffDelete, line: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	// The operations were constructed in reverse
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// hunkRange formats one side of a unified diff hunk header.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	} else if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// unifiedDiff computes a unified diff between the old and new
+// contents of the named file.  An empty string is returned if the
+// contents are identical.
+func unifiedDiff(name string, oldText, newText []byte) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	// Compute the line offsets of each operation and select the
+	// operations to include
+	aIdx := make([]int, len(ops)+1)
+	bIdx := make([]int, len(ops)+1)
+	include := make([]bool, len(ops))
+	changed := false
+	for i, op := range ops {
+		aIdx[i+1], bIdx[i+1] = aIdx[i], bIdx[i]
+		if op.kind != diffInsert {
+			aIdx[i+1]++
+		}
+		if op.kind != diffDelete {
+			bIdx[i+1]++
+		}
+
+		if op.kind != diffEqual {
+			changed = true
+			for j := i - diffContext; j <= i+diffContext; j++ {
+				if j >= 0 && j < len(ops) {
+					include[j] = true
+				}
+			}
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// Emit the header and the hunks
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "--- a/%s\n+++ b/%s\n", name, name)
+	for i := 0; i < len(ops); {
+		if !include[i] {
+			i++
+			continue
+		}
+
+		// Find the end of the hunk
+		j := i
+		for j < len(ops) && include[j] {
+			j++
+		}
+
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(aIdx[i], aIdx[j]-aIdx[i]), hunkRange(bIdx[i], bIdx[j]-bIdx[i]))
+		for _, op := range ops[i:j] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = j
+	}
+
+	return buf.String()
+}
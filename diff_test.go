@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLinesEmpty(t *testing.T) {
+	result := splitLines([]byte{})
+
+	assert.Nil(t, result)
+}
+
+func TestSplitLinesTrailingNewline(t *testing.T) {
+	result := splitLines([]byte("a\nb\n"))
+
+	assert.Equal(t, []string{"a\n", "b\n"}, result)
+}
+
+func TestSplitLinesNoTrailingNewline(t *testing.T) {
+	result := splitLines([]byte("a\nb"))
+
+	assert.Equal(t, []string{"a\n", "b"}, result)
+}
+
+func TestDiffLinesEmpty(t *testing.T) {
+	result := diffLines(nil, nil)
+
+	assert.Nil(t, result)
+}
+
+func TestDiffLinesBase(t *testing.T) {
+	result := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+
+	assert.Equal(t, []diffOp{
+		{kind: diffEqual, line: "a"},
+		{kind: diffDelete, line: "b"},
+		{kind: diffInsert, line: "x"},
+		{kind: diffEqual, line: "c"},
+		{kind: diffInsert, line: "d"},
+	}, result)
+}
+
+func TestDiffLinesAllInserted(t *testing.T) {
+	result := diffLines(nil, []string{"a", "b"})
+
+	assert.Equal(t, []diffOp{
+		{kind: diffInsert, line: "a"},
+		{kind: diffInsert, line: "b"},
+	}, result)
+}
+
+func TestDiffLinesAllDeleted(t *testing.T) {
+	result := diffLines([]string{"a", "b"}, nil)
+
+	assert.Equal(t, []diffOp{
+		{kind: diffDelete, line: "a"},
+		{kind: diffDelete, line: "b"},
+	}, result)
+}
+
+func TestHunkRange(t *testing.T) {
+	assert.Equal(t, "4,0", hunkRange(4, 0))
+	assert.Equal(t, "5", hunkRange(4, 1))
+	assert.Equal(t, "5,3", hunkRange(4, 3))
+}
+
+func TestUnifiedDiffIdentical(t *testing.T) {
+	result := unifiedDiff("file.cfg", []byte("a\nb\n"), []byte("a\nb\n"))
+
+	assert.Equal(t, "", result)
+}
+
+func TestUnifiedDiffHunks(t *testing.T) {
+	oldText := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	newText := []byte("one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n")
+
+	result := unifiedDiff("file.cfg", oldText, newText)
+
+	assert.Equal(t, `--- a/file.cfg
++++ b/file.cfg
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`, result)
+}
+
+func TestUnifiedDiffMergedHunk(t *testing.T) {
+	oldText := []byte("1\n2\n3\n4\n5\n")
+	newText := []byte("one\n2\n3\n4\nfive\n")
+
+	result := unifiedDiff("file.cfg", oldText, newText)
+
+	assert.Equal(t, `--- a/file.cfg
++++ b/file.cfg
+@@ -1,5 +1,5 @@
+-1
++one
+ 2
+ 3
+ 4
+-5
++five
+`, result)
+}
+
+func TestUnifiedDiffNoNewline(t *testing.T) {
+	result := unifiedDiff("file.cfg", []byte("a"), []byte("b"))
+
+	assert.Equal(t, `--- a/file.cfg
++++ b/file.cfg
+@@ -1 +1 @@
+-a
+\ No newline at end of file
++b
+\ No newline at end of file
+`, result)
+}
+
+func TestUnifiedDiffEmptyOld(t *testing.T) {
+	result := unifiedDiff("file.cfg", nil, []byte("a\n"))
+
+	assert.Equal(t, `--- a/file.cfg
++++ b/file.cfg
+@@ -0,0 +1 @@
++a
+`, result)
+}
@@ -57,6 +57,31 @@ func (sw *stringWarning) Unwrap() error {
 	return sw.err
 }
 
+// multiWarning is an implementation of Warning that wraps multiple
+// errors, as constructed by Warningf with multiple %w verbs.
+type multiWarning struct {
+	msg  string  // The warning message
+	errs []error // The wrapped errors
+}
+
+// Error returns the error message.
+func (mw *multiWarning) Error() string {
+	return mw.msg
+}
+
+// Warning is the equivalent to Error for the error interface.  It
+// should return the text of the warning, and so should be equivalent
+// to Error; the presence of this method is the signal that an error
+// is a Warning.
+func (mw *multiWarning) Warning() string {
+	return mw.msg
+}
+
+// Unwrap returns the wrapped errors.
+func (mw *multiWarning) Unwrap() []error {
+	return mw.errs
+}
+
 // NewWarning constructs a new simple warning.  It is the equivalent
 // of errors.New for warnings.
 func NewWarning(text string) error {
@@ -73,28 +98,66 @@ func WarningWrap(err error) error {
 	}
 }
 
-// Warningf constructs a new warning potentially wrapping another
-// error or warning.  It is the equivalent of fmt.Errorsf for
-// warnings.
+// Warningf constructs a new warning potentially wrapping other errors
+// or warnings.  It is the equivalent of fmt.Errorf for warnings;
+// like fmt.Errorf, if the format contains multiple %w verbs, all the
+// wrapped errors are preserved.
 func Warningf(format string, args ...interface{}) error {
 	// Use Errorf to do the work
 	tmp := fmt.Errorf(format, args...) //nolint:goerr113
 
 	// Now make it a warning
+	if multi, ok := tmp.(interface{ Unwrap() []error }); ok {
+		return &multiWarning{
+			msg:  tmp.Error(),
+			errs: multi.Unwrap(),
+		}
+	}
+
 	return &stringWarning{
 		msg: tmp.Error(),
 		err: errors.Unwrap(tmp),
 	}
 }
 
-// IsWarning checks an error to determine if it is a warning.  It
-// returns true if the error is a warning, false otherwise.
-func IsWarning(err error) bool {
-	for ; err != nil; err = errors.Unwrap(err) {
-		if _, ok := err.(Warning); ok {
+// walk is a helper that follows the tree of wrapped errors, calling
+// the visit function on each one.  Errors that wrap multiple errors
+// (with an "Unwrap() []error" method, as with errors.Join) are
+// explored depth-first, in order.  The walk stops when visit returns
+// true, and walk returns that value.
+func walk(err error, visit func(error) bool) bool {
+	for err != nil {
+		if visit(err) {
 			return true
 		}
+
+		switch tmp := err.(type) {
+		case interface{ Unwrap() error }:
+			err = tmp.Unwrap()
+
+		case interface{ Unwrap() []error }:
+			for _, e := range tmp.Unwrap() {
+				if walk(e, visit) {
+					return true
+				}
+			}
+
+			return false
+
+		default:
+			return false
+		}
 	}
 
 	return false
 }
+
+// IsWarning checks an error to determine if it is a warning.  It
+// returns true if the error is a warning, false otherwise.  An error
+// is a warning if SeverityOf returns SeverityWarning; this includes
+// any error which wraps a Warning without first wrapping an error
+// with some other severity.  An error constructed by errors.Join is
+// only a warning if all the joined errors are warnings.
+func IsWarning(err error) bool {
+	return SeverityOf(err) == SeverityWarning
+}
@@ -15,6 +15,7 @@
 package kent
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -59,6 +60,41 @@ func TestStringWarningUnwrap(t *testing.T) {
 	assert.Same(t, assert.AnError, result)
 }
 
+func TestMultiWarningImplementsWarning(t *testing.T) {
+	assert.Implements(t, (*Warning)(nil), &multiWarning{})
+}
+
+func TestMultiWarningError(t *testing.T) {
+	obj := &multiWarning{
+		msg: "some message",
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, "some message", result)
+}
+
+func TestMultiWarningWarning(t *testing.T) {
+	obj := &multiWarning{
+		msg: "some message",
+	}
+
+	result := obj.Warning()
+
+	assert.Equal(t, "some message", result)
+}
+
+func TestMultiWarningUnwrap(t *testing.T) {
+	err := errors.New("other error") //nolint:goerr113
+	obj := &multiWarning{
+		errs: []error{assert.AnError, err},
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []error{assert.AnError, err}, result)
+}
+
 func TestNewWarning(t *testing.T) {
 	obj := NewWarning("some message")
 
@@ -85,6 +121,18 @@ func TestWarningf(t *testing.T) {
 	}, obj)
 }
 
+func TestWarningfMulti(t *testing.T) {
+	err := errors.New("other error") //nolint:goerr113
+
+	obj := Warningf("this is a test %w and %w", assert.AnError, err)
+
+	assert.Equal(t, &multiWarning{
+		msg:  fmt.Sprintf("this is a test %s and %s", assert.AnError, err),
+		errs: []error{assert.AnError, err},
+	}, obj)
+	assert.True(t, errors.Is(obj, err))
+}
+
 func TestIsWarningDirect(t *testing.T) {
 	err := NewWarning("test warning")
 
@@ -108,3 +156,93 @@ func TestIsWarningErrorOnly(t *testing.T) {
 
 	assert.False(t, result)
 }
+
+func TestIsWarningSeverity(t *testing.T) {
+	err := NewSeverity(SeverityWarning, "test warning")
+
+	result := IsWarning(err)
+
+	assert.True(t, result)
+}
+
+func TestIsWarningOtherSeverity(t *testing.T) {
+	err := SeverityWrap(SeverityError, NewWarning("test warning"))
+
+	result := IsWarning(err)
+
+	assert.False(t, result)
+}
+
+func TestWalkStops(t *testing.T) {
+	inner := errors.New("inner") //nolint:goerr113
+	err := fmt.Errorf("outer: %w", inner)
+	visited := []error{}
+
+	result := walk(err, func(e error) bool {
+		visited = append(visited, e)
+		return e == inner
+	})
+
+	assert.True(t, result)
+	assert.Equal(t, []error{err, inner}, visited)
+}
+
+func TestWalkExhausted(t *testing.T) {
+	err := fmt.Errorf("outer: %w", assert.AnError)
+	visited := []error{}
+
+	result := walk(err, func(e error) bool {
+		visited = append(visited, e)
+		return false
+	})
+
+	assert.False(t, result)
+	assert.Equal(t, []error{err, assert.AnError}, visited)
+}
+
+func TestIsWarningJoined(t *testing.T) {
+	err := errors.Join(assert.AnError, NewWarning("test warning"))
+
+	result := IsWarning(err)
+
+	assert.False(t, result)
+}
+
+func TestIsWarningJoinedWarnings(t *testing.T) {
+	err := errors.Join(NewWarning("test warning 1"), NewWarning("test warning 2"))
+
+	result := IsWarning(err)
+
+	assert.True(t, result)
+}
+
+func TestWalkTree(t *testing.T) {
+	err1 := errors.New("error 1") //nolint:goerr113
+	err2 := errors.New("error 2") //nolint:goerr113
+	inner := fmt.Errorf("inner: %w", err1)
+	err := errors.Join(inner, err2)
+	visited := []error{}
+
+	result := walk(err, func(e error) bool {
+		visited = append(visited, e)
+		return false
+	})
+
+	assert.False(t, result)
+	assert.Equal(t, []error{err, inner, err1, err2}, visited)
+}
+
+func TestWalkTreeStops(t *testing.T) {
+	err1 := errors.New("error 1") //nolint:goerr113
+	err2 := errors.New("error 2") //nolint:goerr113
+	err := errors.Join(err1, err2)
+	visited := []error{}
+
+	result := walk(err, func(e error) bool {
+		visited = append(visited, e)
+		return e == err1
+	})
+
+	assert.True(t, result)
+	assert.Equal(t, []error{err, err1}, visited)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrFatal is the sentinel error returned by Check if a fatal error
+// has been reported.
+var ErrFatal = errors.New("fatal error reported")
+
+// NewFatal constructs a new simple error with SeverityFatal.  It is
+// the equivalent of errors.New for fatal errors.
+func NewFatal(text string) error {
+	return NewSeverity(SeverityFatal, text)
+}
+
+// Fatalf constructs a new error with SeverityFatal, potentially
+// wrapping other errors.  It is the equivalent of fmt.Errorf for
+// fatal errors.
+func Fatalf(format string, args ...interface{}) error {
+	return Severityf(SeverityFatal, format, args...)
+}
+
+// FatalReporter is a Reporter that watches for errors with
+// SeverityFatal.  The first fatal error reported is recorded, and an
+// optional context.CancelFunc is called, allowing the operation to be
+// aborted cleanly.
+type FatalReporter struct {
+	sync.Mutex
+
+	fatal  error              // The first fatal error reported
+	cancel context.CancelFunc // Function to call on a fatal error
+	rep    Reporter           // Child reporter
+}
+
+// FatalReporterOption describes an option for a FatalReporter.
+type FatalReporterOption func(*FatalReporter)
+
+// FatalCancel specifies a context.CancelFunc to call when the first
+// fatal error is reported.
+func FatalCancel(cancel context.CancelFunc) FatalReporterOption {
+	return func(fr *FatalReporter) {
+		fr.cancel = cancel
+	}
+}
+
+// NewFatalReporter constructs a new FatalReporter.  A fatal reporter
+// records the first error with SeverityFatal that is reported, which
+// may be retrieved with the Fatal method or checked with the Check
+// helper; if the FatalCancel option is passed, the specified
+// context.CancelFunc is also called.
+func NewFatalReporter(rep Reporter, options ...FatalReporterOption) *FatalReporter {
+	obj := &FatalReporter{
+		rep: rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (fr *FatalReporter) Report(err error) {
+	// Record the first fatal error
+	first := false
+	if SeverityOf(err) == SeverityFatal {
+		fr.Lock()
+		if fr.fatal == nil {
+			fr.fatal = err
+			first = true
+		}
+		fr.Unlock()
+	}
+
+	// Pass on to child
+	fr.rep.Report(err)
+
+	// Abort the operation
+	if first && fr.cancel != nil {
+		fr.cancel()
+	}
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (fr *FatalReporter) Unwrap() []Reporter {
+	return []Reporter{fr.rep}
+}
+
+// Fatal returns the first fatal error reported using the
+// FatalReporter, or nil if no fatal error has been reported.
+func (fr *FatalReporter) Fatal() error {
+	// Lock the mutex for thread safety
+	fr.Lock()
+	defer fr.Unlock()
+
+	return fr.fatal
+}
+
+// Check is a helper that checks whether a fatal error has been
+// reported.  It uses As to find a FatalReporter in the chain of
+// Reporters; if one is found and a fatal error has been reported
+// using it, an error wrapping both ErrFatal and the fatal error is
+// returned.  Otherwise, nil is returned.  This allows processing
+// stages to bail out consistently with code such as:
+//
+//	if err := kent.Check(rep); err != nil {
+//		return err
+//	}
+func Check(rep Reporter) error {
+	var fr *FatalReporter
+	if !As(rep, &fr) {
+		return nil
+	}
+
+	if fatal := fr.Fatal(); fatal != nil {
+		return fmt.Errorf("%w: %w", ErrFatal, fatal)
+	}
+
+	return nil
+}
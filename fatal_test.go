@@ -0,0 +1,215 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFatal(t *testing.T) {
+	obj := NewFatal("some message")
+
+	assert.Equal(t, &severityError{
+		sev: SeverityFatal,
+		msg: "some message",
+	}, obj)
+}
+
+func TestFatalf(t *testing.T) {
+	obj := Fatalf("this is a test %w", assert.AnError)
+
+	assert.Equal(t, &severityError{
+		sev: SeverityFatal,
+		msg: "this is a test " + assert.AnError.Error(),
+		err: assert.AnError,
+	}, obj)
+}
+
+func TestFatalReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &FatalReporter{})
+}
+
+func TestFatalCancel(t *testing.T) {
+	cancelCalled := false
+	obj := &FatalReporter{}
+
+	opt := FatalCancel(func() { cancelCalled = true })
+	opt(obj)
+
+	assert.NotNil(t, obj.cancel)
+	obj.cancel()
+	assert.True(t, cancelCalled)
+}
+
+func TestNewFatalReporterBase(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewFatalReporter(rep)
+
+	assert.Equal(t, &FatalReporter{
+		rep: rep,
+	}, result)
+}
+
+func TestNewFatalReporterOptions(t *testing.T) {
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *FatalReporter
+	options := []FatalReporterOption{
+		func(fr *FatalReporter) {
+			opt1Called = fr
+		},
+		func(fr *FatalReporter) {
+			opt2Called = fr
+		},
+	}
+
+	result := NewFatalReporter(rep, options...)
+
+	assert.Equal(t, &FatalReporter{
+		rep: rep,
+	}, result)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestFatalReporterReportNonFatal(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError)
+	cancelCalled := false
+	obj := &FatalReporter{
+		cancel: func() { cancelCalled = true },
+		rep:    rep,
+	}
+
+	obj.Report(assert.AnError)
+
+	assert.Nil(t, obj.fatal)
+	assert.False(t, cancelCalled)
+	rep.AssertExpectations(t)
+}
+
+func TestFatalReporterReportFatal(t *testing.T) {
+	err := NewFatal("fatal")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	cancelCalled := 0
+	obj := &FatalReporter{
+		cancel: func() { cancelCalled++ },
+		rep:    rep,
+	}
+
+	obj.Report(err)
+
+	assert.Same(t, err, obj.fatal)
+	assert.Equal(t, 1, cancelCalled)
+	rep.AssertExpectations(t)
+}
+
+func TestFatalReporterReportFatalNoCancel(t *testing.T) {
+	err := NewFatal("fatal")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	obj := &FatalReporter{
+		rep: rep,
+	}
+
+	obj.Report(err)
+
+	assert.Same(t, err, obj.fatal)
+	rep.AssertExpectations(t)
+}
+
+func TestFatalReporterReportSecondFatal(t *testing.T) {
+	first := NewFatal("first")
+	err := NewFatal("second")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	cancelCalled := 0
+	obj := &FatalReporter{
+		fatal:  first,
+		cancel: func() { cancelCalled++ },
+		rep:    rep,
+	}
+
+	obj.Report(err)
+
+	assert.Same(t, first, obj.fatal)
+	assert.Equal(t, 0, cancelCalled)
+	rep.AssertExpectations(t)
+}
+
+func TestFatalReporterReportCancelsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obj := NewFatalReporter(Root(), FatalCancel(cancel))
+
+	obj.Report(NewFatal("fatal"))
+
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestFatalReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &FatalReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestFatalReporterFatal(t *testing.T) {
+	obj := &FatalReporter{
+		fatal: assert.AnError,
+	}
+
+	result := obj.Fatal()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestCheckNoFatalReporter(t *testing.T) {
+	rep := NewCountingReporter(Root())
+
+	result := Check(rep)
+
+	assert.NoError(t, result)
+}
+
+func TestCheckNoFatal(t *testing.T) {
+	rep := NewCountingReporter(NewFatalReporter(Root()))
+	rep.Report(assert.AnError)
+
+	result := Check(rep)
+
+	assert.NoError(t, result)
+}
+
+func TestCheckFatal(t *testing.T) {
+	err := NewFatal("fatal")
+	rep := NewCountingReporter(NewFatalReporter(Root()))
+	rep.Report(err)
+
+	result := Check(rep)
+
+	assert.True(t, errors.Is(result, ErrFatal))
+	assert.True(t, errors.Is(result, err))
+	assert.EqualError(t, result, "fatal error reported: fatal")
+}
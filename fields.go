@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// badKey is the key used by WithFields for a value without a key.
+const badKey = "!BADKEY"
+
+// Fielded is a utility interface for errors that carry structured
+// key/value fields.  The Fields helper merges the fields from all
+// errors in an error chain that implement Fielded.
+type Fielded interface {
+	error
+
+	// Fields returns the fields attached to the error.
+	Fields() map[string]interface{}
+}
+
+// fieldsError is an implementation of Fielded that wraps another
+// error.
+type fieldsError struct {
+	fields map[string]interface{} // The fields
+	err    error                  // The wrapped error
+}
+
+// Error returns the error message.
+func (fe *fieldsError) Error() string {
+	return fe.err.Error()
+}
+
+// Fields returns the fields attached to the error.
+func (fe *fieldsError) Fields() map[string]interface{} {
+	return fe.fields
+}
+
+// Unwrap returns the wrapped error.
+func (fe *fieldsError) Unwrap() error {
+	return fe.err
+}
+
+// WithFields attaches structured key/value fields to an error.  The
+// kv arguments alternate between keys and values; keys that are not
+// strings are converted to strings using fmt.Sprint, and a final
+// value without a key is given the key "!BADKEY".  The error message
+// is not altered.
+func WithFields(err error, kv ...interface{}) error {
+	fields := make(map[string]interface{}, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 >= len(kv) {
+			fields[badKey] = kv[i]
+			break
+		}
+
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		fields[key] = kv[i+1]
+	}
+
+	return &fieldsError{
+		fields: fields,
+		err:    err,
+	}
+}
+
+// Fields retrieves the structured fields associated with an error.
+// It follows the entire error chain and merges the fields from every
+// error implementing Fielded; if the same key is present at multiple
+// layers, the value from the outermost error is used.  If the error
+// has no fields, nil is returned.
+func Fields(err error) map[string]interface{} {
+	var fields map[string]interface{}
+	walk(err, func(e error) bool {
+		if fe, ok := e.(Fielded); ok {
+			for key, value := range fe.Fields() {
+				if fields == nil {
+					fields = map[string]interface{}{}
+				}
+				if _, ok := fields[key]; !ok {
+					fields[key] = value
+				}
+			}
+		}
+
+		return false
+	})
+
+	return fields
+}
+
+// formatValue is a helper that formats a field value, quoting it if
+// it is empty or contains spaces, quotes, or equals signs.
+func formatValue(value interface{}) string {
+	text := fmt.Sprint(value)
+	if text == "" || strings.ContainsAny(text, " \t\r\n\"=") {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
+
+// joinFields is a helper that formats a set of fields as a
+// space-separated list of key=value pairs, sorted by key.
+func joinFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + formatValue(fields[key])
+	}
+
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsErrorImplementsFielded(t *testing.T) {
+	assert.Implements(t, (*Fielded)(nil), &fieldsError{})
+}
+
+func TestFieldsErrorError(t *testing.T) {
+	obj := &fieldsError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestFieldsErrorFields(t *testing.T) {
+	obj := &fieldsError{
+		fields: map[string]interface{}{"key": "value"},
+	}
+
+	result := obj.Fields()
+
+	assert.Equal(t, map[string]interface{}{"key": "value"}, result)
+}
+
+func TestFieldsErrorUnwrap(t *testing.T) {
+	obj := &fieldsError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestWithFieldsBase(t *testing.T) {
+	result := WithFields(assert.AnError, "key1", "value1", "key2", 42)
+
+	assert.Equal(t, &fieldsError{
+		fields: map[string]interface{}{
+			"key1": "value1",
+			"key2": 42,
+		},
+		err: assert.AnError,
+	}, result)
+}
+
+func TestWithFieldsBadKeys(t *testing.T) {
+	result := WithFields(assert.AnError, 17, "value1", "value2")
+
+	assert.Equal(t, &fieldsError{
+		fields: map[string]interface{}{
+			"17":   "value1",
+			badKey: "value2",
+		},
+		err: assert.AnError,
+	}, result)
+}
+
+func TestFieldsChain(t *testing.T) {
+	err := WithFields(
+		fmt.Errorf("wrapped: %w", WithFields(assert.AnError, "key1", "inner", "key2", "inner")),
+		"key1", "outer",
+	)
+
+	result := Fields(err)
+
+	assert.Equal(t, map[string]interface{}{
+		"key1": "outer",
+		"key2": "inner",
+	}, result)
+}
+
+func TestFieldsMissing(t *testing.T) {
+	result := Fields(assert.AnError)
+
+	assert.Nil(t, result)
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "value", formatValue("value"))
+	assert.Equal(t, "42", formatValue(42))
+	assert.Equal(t, `""`, formatValue(""))
+	assert.Equal(t, `"two words"`, formatValue("two words"))
+	assert.Equal(t, `"a=b"`, formatValue("a=b"))
+	assert.Equal(t, `"say \"hi\""`, formatValue(`say "hi"`))
+}
+
+func TestJoinFields(t *testing.T) {
+	result := joinFields(map[string]interface{}{
+		"b": 2,
+		"a": "one",
+		"c": "three four",
+	})
+
+	assert.Equal(t, `a=one b=2 c="three four"`, result)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import "sync/atomic"
+
+// FilteringReporter is a Reporter that passes on only those errors
+// selected by a Predicate to the wrapped Reporter.  The number of
+// errors that were filtered out is maintained.
+type FilteringReporter struct {
+	filtered int64     // Number of errors filtered out
+	pred     Predicate // Predicate selecting errors to pass on
+	rep      Reporter  // Child reporter
+}
+
+// NewFilteringReporter constructs a new filtering Reporter.  A
+// filtering reporter passes on the reported errors selected by the
+// specified Predicate to the wrapped Reporter, and discards the
+// others; the number of errors discarded is available through the
+// Filtered method.  For example, to pass on only warnings:
+//
+//	rep = kent.NewFilteringReporter(rep, kent.IsWarningPred)
+//
+// or to discard errors matching fs.ErrNotExist:
+//
+//	rep = kent.NewFilteringReporter(rep, kent.Not(kent.MatchesIs(fs.ErrNotExist)))
+func NewFilteringReporter(rep Reporter, pred Predicate) *FilteringReporter {
+	return &FilteringReporter{
+		pred: pred,
+		rep:  rep,
+	}
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (fr *FilteringReporter) Report(err error) {
+	if !fr.pred(err) {
+		atomic.AddInt64(&fr.filtered, 1)
+		return
+	}
+
+	fr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (fr *FilteringReporter) Unwrap() []Reporter {
+	return []Reporter{fr.rep}
+}
+
+// Filtered returns the number of errors filtered out so far by the
+// filtering reporter.
+func (fr *FilteringReporter) Filtered() int {
+	return int(atomic.LoadInt64(&fr.filtered))
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilteringReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &FilteringReporter{})
+}
+
+func TestNewFilteringReporter(t *testing.T) {
+	rep := &MockReporter{}
+	predCalled := false
+
+	result := NewFilteringReporter(rep, func(err error) bool {
+		predCalled = true
+		return true
+	})
+
+	assert.Same(t, rep, result.rep)
+	assert.Equal(t, int64(0), result.filtered)
+	assert.True(t, result.pred(assert.AnError))
+	assert.True(t, predCalled)
+}
+
+func TestFilteringReporterReportSelected(t *testing.T) {
+	err := NewWarning("a warning")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	obj := &FilteringReporter{
+		pred: IsWarningPred,
+		rep:  rep,
+	}
+
+	obj.Report(err)
+
+	assert.Equal(t, int64(0), obj.filtered)
+	rep.AssertExpectations(t)
+}
+
+func TestFilteringReporterReportFiltered(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &FilteringReporter{
+		pred: IsWarningPred,
+		rep:  rep,
+	}
+
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, int64(1), obj.filtered)
+	rep.AssertExpectations(t)
+}
+
+func TestFilteringReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &FilteringReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestFilteringReporterFiltered(t *testing.T) {
+	obj := &FilteringReporter{
+		filtered: 42,
+	}
+
+	result := obj.Filtered()
+
+	assert.Equal(t, 42, result)
+}
+
+func TestFilteringReporterAs(t *testing.T) {
+	counter := NewCountingReporter(Root())
+	obj := NewFilteringReporter(counter, Not(IsWarningPred))
+	obj.Report(assert.AnError)
+	obj.Report(NewWarning("a warning"))
+
+	var target *CountingReporter
+	result := As(obj, &target)
+
+	assert.True(t, result)
+	assert.Same(t, counter, target)
+	assert.Equal(t, 1, target.Errors())
+	assert.Equal(t, 0, target.Warnings())
+	assert.Equal(t, 1, obj.Filtered())
+}
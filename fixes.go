@@ -0,0 +1,241 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidEdit is returned by ApplyFixes if a TextEdit has a range
+// that is not valid for the file it applies to.
+var ErrInvalidEdit = errors.New("invalid text edit")
+
+// FixFS describes a file system that ApplyFixes can read files from
+// and write files to.
+type FixFS interface {
+	// ReadFile reads the named file and returns its contents.
+	ReadFile(name string) ([]byte, error)
+
+	// WriteFile replaces the contents of the named file.
+	WriteFile(name string, data []byte) error
+}
+
+// DirFixFS is an implementation of FixFS for the files in the named
+// directory of the operating system's file system.  File names are
+// slash-separated and relative to the directory; names that are not
+// valid as described by fs.ValidPath, such as names containing ".."
+// elements, are rejected with an error wrapping ErrInvalidEdit, so
+// that edits cannot reach files outside the directory.
+type DirFixFS string
+
+// path is a helper that converts a file name into a path within the
+// directory.  An error wrapping ErrInvalidEdit is returned if the
+// name is not valid.
+func (d DirFixFS) path(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("%w: invalid file name %q", ErrInvalidEdit, name)
+	}
+
+	return filepath.Join(string(d), filepath.FromSlash(name)), nil
+}
+
+// ReadFile reads the named file and returns its contents.
+func (d DirFixFS) ReadFile(name string) ([]byte, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+// WriteFile replaces the contents of the named file.  The mode of an
+// existing file is preserved.
+func (d DirFixFS) WriteFile(name string, data []byte) error {
+	path, err := d.path(name)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	return os.WriteFile(path, data, mode)
+}
+
+// FixResult describes the result of applying suggested fixes.
+type FixResult struct {
+	Applied   []Suggestion // Suggestions that were applied
+	Conflicts []Suggestion // Suggestions skipped due to conflicts
+	Files     []string     // Sorted list of the modified files
+	Diff      string       // Unified diff of the changes
+}
+
+// fixOptions contains the options for ApplyFixes.
+type fixOptions struct {
+	dryRun bool // If true, don't write the files
+}
+
+// FixOption is an option for ApplyFixes.
+type FixOption func(*fixOptions)
+
+// FixDryRun specifies that ApplyFixes should not write the modified
+// files.  The unified diff in the FixResult describes the changes
+// that would have been made.
+func FixDryRun() FixOption {
+	return func(o *fixOptions) {
+		o.dryRun = true
+	}
+}
+
+// overlaps is a helper that determines if two edits conflict with
+// each other.  Edits conflict if their ranges overlap, or if both
+// insert text at the same offset.
+func overlaps(e1, e2 TextEdit) bool {
+	if e1.File != e2.File {
+		return false
+	}
+
+	if e1.Start == e1.End && e2.Start == e2.End {
+		return e1.Start == e2.Start
+	}
+
+	return e1.Start < e2.End && e2.Start < e1.End
+}
+
+// applyEdits applies a list of non-overlapping edits to a file's
+// contents, returning the new contents.
+func applyEdits(data []byte, edits []TextEdit) []byte {
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].Start != edits[j].Start {
+			return edits[i].Start < edits[j].Start
+		}
+
+		return edits[i].End < edits[j].End
+	})
+
+	buf := &strings.Builder{}
+	last := 0
+	for _, edit := range edits {
+		buf.Write(data[last:edit.Start])
+		buf.WriteString(edit.NewText)
+		last = edit.End
+	}
+	buf.Write(data[last:])
+
+	return []byte(buf.String())
+}
+
+// ApplyFixes applies the suggested fixes attached to a list of
+// diagnostics (see Suggestions) to the files in a FixFS.  The edits
+// of each suggestion are applied together or not at all; if any edit
+// of a suggestion overlaps an edit of a previously accepted
+// suggestion, the suggestion is skipped and listed in the Conflicts
+// field of the result.  Edits identical to an already accepted edit
+// are not applied twice.  If an edit has an invalid range, an error
+// wrapping ErrInvalidEdit is returned and no files are modified.
+// The FixDryRun option may be used to compute the unified diff of
+// the changes without modifying any files.
+func ApplyFixes(diags []error, fsys FixFS, options ...FixOption) (*FixResult, error) {
+	opts := &fixOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	// Select the suggestions to apply
+	result := &FixResult{}
+	contents := map[string][]byte{}
+	accepted := map[string][]TextEdit{}
+	for _, diag := range diags {
+	suggestions:
+		for _, sug := range Suggestions(diag) {
+			newEdits := []TextEdit{}
+			for _, edit := range sug.Edits {
+				// Load the file contents
+				data, ok := contents[edit.File]
+				if !ok {
+					var err error
+					if data, err = fsys.ReadFile(edit.File); err != nil {
+						return nil, err
+					}
+					contents[edit.File] = data
+				}
+
+				// Validate the edit
+				if edit.Start < 0 || edit.End < edit.Start || edit.End > len(data) {
+					return nil, fmt.Errorf("%w: %s: [%d, %d)", ErrInvalidEdit, edit.File, edit.Start, edit.End)
+				}
+
+				// Check for conflicts
+				dup := false
+				for _, other := range accepted[edit.File] {
+					if other == edit {
+						dup = true
+					} else if overlaps(edit, other) {
+						result.Conflicts = append(result.Conflicts, sug)
+						continue suggestions
+					}
+				}
+				for _, other := range newEdits {
+					if overlaps(edit, other) {
+						result.Conflicts = append(result.Conflicts, sug)
+						continue suggestions
+					}
+				}
+				if !dup {
+					newEdits = append(newEdits, edit)
+				}
+			}
+
+			// Accept the suggestion
+			for _, edit := range newEdits {
+				accepted[edit.File] = append(accepted[edit.File], edit)
+			}
+			result.Applied = append(result.Applied, sug)
+		}
+	}
+
+	// Compute the new contents of the files
+	for name := range accepted {
+		result.Files = append(result.Files, name)
+	}
+	sort.Strings(result.Files)
+	updated := map[string][]byte{}
+	diff := &strings.Builder{}
+	for _, name := range result.Files {
+		updated[name] = applyEdits(contents[name], accepted[name])
+		diff.WriteString(unifiedDiff(name, contents[name], updated[name]))
+	}
+	result.Diff = diff.String()
+
+	// Write the files
+	if !opts.dryRun {
+		for _, name := range result.Files {
+			if err := fsys.WriteFile(name, updated[name]); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapFixFS is an implementation of FixFS for testing.
+type mapFixFS map[string]string
+
+func (m mapFixFS) ReadFile(name string) ([]byte, error) {
+	if data, ok := m[name]; ok {
+		return []byte(data), nil
+	}
+
+	return nil, fs.ErrNotExist
+}
+
+func (m mapFixFS) WriteFile(name string, data []byte) error {
+	m[name] = string(data)
+
+	return nil
+}
+
+func TestDirFixFSReadFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.cfg"), []byte("contents"), 0o600))
+	obj := DirFixFS(dir)
+
+	result, err := obj.ReadFile("file.cfg")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("contents"), result)
+}
+
+func TestDirFixFSWriteFileExisting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.cfg")
+	require.NoError(t, os.WriteFile(path, []byte("contents"), 0o600))
+	obj := DirFixFS(dir)
+
+	err := obj.WriteFile("file.cfg", []byte("new contents"))
+
+	assert.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new contents"), data)
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestDirFixFSWriteFileNew(t *testing.T) {
+	dir := t.TempDir()
+	obj := DirFixFS(dir)
+
+	err := obj.WriteFile("file.cfg", []byte("new contents"))
+
+	assert.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "file.cfg"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new contents"), data)
+}
+
+func TestDirFixFSReadFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret"), []byte("contents"), 0o600))
+	obj := DirFixFS(filepath.Join(dir, "sub"))
+
+	for _, name := range []string{"../secret", "/secret", "a/../../secret"} {
+		result, err := obj.ReadFile(name)
+
+		assert.ErrorIs(t, err, ErrInvalidEdit, name)
+		assert.Nil(t, result, name)
+	}
+}
+
+func TestDirFixFSWriteFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(path, []byte("contents"), 0o600))
+	obj := DirFixFS(filepath.Join(dir, "sub"))
+
+	err := obj.WriteFile("../secret", []byte("new contents"))
+
+	assert.ErrorIs(t, err, ErrInvalidEdit)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("contents"), data)
+}
+
+func TestApplyFixesDirFixFSEscape(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(path, []byte("contents"), 0o600))
+	sub := filepath.Join(dir, "sub")
+	require.NoError(t, os.Mkdir(sub, 0o700))
+	diag := WithSuggestions(assert.AnError, Suggestion{
+		Description: "overwrite",
+		Edits:       []TextEdit{{File: "../secret", Start: 0, End: 8, NewText: "pwned"}},
+	})
+
+	result, err := ApplyFixes([]error{diag}, DirFixFS(sub))
+
+	assert.ErrorIs(t, err, ErrInvalidEdit)
+	assert.Nil(t, result)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("contents"), data)
+}
+
+func TestFixDryRun(t *testing.T) {
+	obj := &fixOptions{}
+
+	opt := FixDryRun()
+	opt(obj)
+
+	assert.True(t, obj.dryRun)
+}
+
+func TestOverlaps(t *testing.T) {
+	assert.False(t, overlaps(TextEdit{File: "a", Start: 0, End: 5}, TextEdit{File: "b", Start: 0, End: 5}))
+	assert.True(t, overlaps(TextEdit{File: "a", Start: 0, End: 5}, TextEdit{File: "a", Start: 4, End: 8}))
+	assert.False(t, overlaps(TextEdit{File: "a", Start: 0, End: 5}, TextEdit{File: "a", Start: 5, End: 8}))
+	assert.True(t, overlaps(TextEdit{File: "a", Start: 3, End: 3}, TextEdit{File: "a", Start: 3, End: 3}))
+	assert.False(t, overlaps(TextEdit{File: "a", Start: 3, End: 3}, TextEdit{File: "a", Start: 4, End: 4}))
+	assert.True(t, overlaps(TextEdit{File: "a", Start: 3, End: 3}, TextEdit{File: "a", Start: 2, End: 4}))
+	assert.False(t, overlaps(TextEdit{File: "a", Start: 2, End: 2}, TextEdit{File: "a", Start: 2, End: 4}))
+}
+
+func TestApplyEdits(t *testing.T) {
+	data := []byte("hello world")
+	edits := []TextEdit{
+		{Start: 6, End: 11, NewText: "there"},
+		{Start: 0, End: 0, NewText: ">> "},
+		{Start: 5, End: 5, NewText: ","},
+	}
+
+	result := applyEdits(data, edits)
+
+	assert.Equal(t, []byte(">> hello, there"), result)
+}
+
+func TestApplyFixesBase(t *testing.T) {
+	fsys := mapFixFS{
+		"a.cfg": "key = valu\nother = 1\n",
+		"b.cfg": "x = 1\n",
+	}
+	sug1 := Suggestion{
+		Description: "fix typo",
+		Edits:       []TextEdit{{File: "a.cfg", Start: 6, End: 10, NewText: "value"}},
+	}
+	sug2 := Suggestion{
+		Description: "add y",
+		Edits:       []TextEdit{{File: "b.cfg", Start: 6, End: 6, NewText: "y = 2\n"}},
+	}
+	diags := []error{
+		WithSuggestions(assert.AnError, sug1),
+		WithSuggestions(NewWarning("warning"), sug2),
+		NewWarning("no suggestions"),
+	}
+
+	result, err := ApplyFixes(diags, fsys)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Suggestion{sug1, sug2}, result.Applied)
+	assert.Nil(t, result.Conflicts)
+	assert.Equal(t, []string{"a.cfg", "b.cfg"}, result.Files)
+	assert.Equal(t, `--- a/a.cfg
++++ b/a.cfg
+@@ -1,2 +1,2 @@
+-key = valu
++key = value
+ other = 1
+--- a/b.cfg
++++ b/b.cfg
+@@ -1 +1,2 @@
+ x = 1
++y = 2
+`, result.Diff)
+	assert.Equal(t, mapFixFS{
+		"a.cfg": "key = value\nother = 1\n",
+		"b.cfg": "x = 1\ny = 2\n",
+	}, fsys)
+}
+
+func TestApplyFixesDryRun(t *testing.T) {
+	fsys := mapFixFS{
+		"a.cfg": "key = valu\n",
+	}
+	sug := Suggestion{
+		Edits: []TextEdit{{File: "a.cfg", Start: 6, End: 10, NewText: "value"}},
+	}
+
+	result, err := ApplyFixes([]error{WithSuggestions(assert.AnError, sug)}, fsys, FixDryRun())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Suggestion{sug}, result.Applied)
+	assert.Equal(t, "--- a/a.cfg\n+++ b/a.cfg\n@@ -1 +1 @@\n-key = valu\n+key = value\n", result.Diff)
+	assert.Equal(t, mapFixFS{
+		"a.cfg": "key = valu\n",
+	}, fsys)
+}
+
+func TestApplyFixesConflict(t *testing.T) {
+	fsys := mapFixFS{
+		"a.cfg": "key = valu\n",
+	}
+	sug1 := Suggestion{
+		Description: "fix typo",
+		Edits:       []TextEdit{{File: "a.cfg", Start: 6, End: 10, NewText: "value"}},
+	}
+	sug2 := Suggestion{
+		Description: "remove value",
+		Edits: []TextEdit{
+			{File: "a.cfg", Start: 0, End: 0, NewText: "# "},
+			{File: "a.cfg", Start: 4, End: 10, NewText: ""},
+		},
+	}
+
+	result, err := ApplyFixes([]error{WithSuggestions(assert.AnError, sug1, sug2)}, fsys)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Suggestion{sug1}, result.Applied)
+	assert.Equal(t, []Suggestion{sug2}, result.Conflicts)
+	assert.Equal(t, mapFixFS{
+		"a.cfg": "key = value\n",
+	}, fsys)
+}
+
+func TestApplyFixesSelfConflict(t *testing.T) {
+	fsys := mapFixFS{
+		"a.cfg": "key = valu\n",
+	}
+	sug := Suggestion{
+		Edits: []TextEdit{
+			{File: "a.cfg", Start: 0, End: 5, NewText: "x"},
+			{File: "a.cfg", Start: 4, End: 10, NewText: "y"},
+		},
+	}
+
+	result, err := ApplyFixes([]error{WithSuggestions(assert.AnError, sug)}, fsys)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.Applied)
+	assert.Equal(t, []Suggestion{sug}, result.Conflicts)
+	assert.Equal(t, "", result.Diff)
+}
+
+func TestApplyFixesDuplicate(t *testing.T) {
+	fsys := mapFixFS{
+		"a.cfg": "key = valu\n",
+	}
+	sug := Suggestion{
+		Edits: []TextEdit{{File: "a.cfg", Start: 6, End: 10, NewText: "value"}},
+	}
+	diags := []error{
+		WithSuggestions(assert.AnError, sug),
+		WithSuggestions(assert.AnError, sug),
+	}
+
+	result, err := ApplyFixes(diags, fsys)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Suggestion{sug, sug}, result.Applied)
+	assert.Nil(t, result.Conflicts)
+	assert.Equal(t, mapFixFS{
+		"a.cfg": "key = value\n",
+	}, fsys)
+}
+
+func TestApplyFixesInvalidEdit(t *testing.T) {
+	fsys := mapFixFS{
+		"a.cfg": "key = valu\n",
+	}
+	sug := Suggestion{
+		Edits: []TextEdit{{File: "a.cfg", Start: 6, End: 100, NewText: "value"}},
+	}
+
+	result, err := ApplyFixes([]error{WithSuggestions(assert.AnError, sug)}, fsys)
+
+	assert.True(t, errors.Is(err, ErrInvalidEdit))
+	assert.Nil(t, result)
+	assert.Equal(t, mapFixFS{
+		"a.cfg": "key = valu\n",
+	}, fsys)
+}
+
+func TestApplyFixesReadError(t *testing.T) {
+	fsys := mapFixFS{}
+	sug := Suggestion{
+		Edits: []TextEdit{{File: "a.cfg", Start: 0, End: 0, NewText: "value"}},
+	}
+
+	result, err := ApplyFixes([]error{WithSuggestions(assert.AnError, sug)}, fsys)
+
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+	assert.Nil(t, result)
+}
@@ -28,10 +28,19 @@ var newFormatters = NewFormatters
 // used for the format of the error.
 type FormatFunc func(err error) string
 
-// Formatters contains formatters for formatting errors and warnings.
+// Formatters contains formatters for formatting errors and warnings,
+// as well as errors of any other severity.
 type Formatters struct {
-	errors   FormatFunc // Format function for handling errors
-	warnings FormatFunc // Format function for handling warnings
+	formats  [numSeverities]FormatFunc // Format functions by severity
+	codes    bool                      // Include codes in the default format
+	fields   bool                      // Append fields to the message
+	inline   [numSeverities]bool       // Formats include fields and notes
+	color    ColorMode                 // When to colorize the default format
+	colorOut bool                      // Output stream supports color
+	theme    ColorTheme                // Colors for the default format
+	snippets *snippetRenderer          // Renderer for source snippets
+	catalog  *Catalog                  // Catalog for localizing messages
+	locale   string                    // Locale for localizing messages
 }
 
 // FormatOption is an option for setting fields of a Formatters
@@ -45,43 +54,111 @@ func formatFromString(format string) FormatFunc {
 	}
 }
 
-// FormatError specifies the format string for formatting errors.
-func FormatError(format string) FormatOption {
+// formatDefault constructs the default FormatFunc for a severity.
+// The message is prefixed with the upper-cased name of the severity
+// and, if the error has a position or a scope, the position and the
+// scope path.  If codes are enabled with FormatCodes, the diagnostic
+// code follows the severity in brackets.
+func (f *Formatters) formatDefault(sev Severity) FormatFunc {
+	label := strings.ToUpper(sev.String())
+
+	return func(err error) string {
+		buf := &strings.Builder{}
+
+		// Begin with the position, if one is known
+		if pos, ok := PositionOf(err); ok && pos.known() {
+			fmt.Fprintf(buf, "%s: ", f.colorize(f.theme.Position, pos.String()))
+		}
+
+		// Add the scope, if there is one
+		if scope := Scope(err); len(scope) > 0 {
+			fmt.Fprintf(buf, "%s: ", f.colorize(f.theme.Scope, joinScope(scope)))
+		}
+
+		// Add the label, the code, and the message
+		buf.WriteString(f.colorize(f.theme.Labels[sev.clamp()], label))
+		if code, ok := CodeOf(err); ok && f.codes {
+			fmt.Fprintf(buf, "[%s]", f.colorize(f.theme.Code, code))
+		}
+		fmt.Fprintf(buf, ": %s", err)
+
+		return buf.String()
+	}
+}
+
+// FormatSeverity specifies the format string for formatting errors
+// with the specified severity.
+func FormatSeverity(sev Severity, format string) FormatOption {
+	return FormatSeverityFunc(sev, formatFromString(strings.TrimRight(format, "\n")))
+}
+
+// FormatSeverityFunc specifies the formatting function for
+// formatting errors with the specified severity.
+func FormatSeverityFunc(sev Severity, fmtFunc FormatFunc) FormatOption {
 	return func(f *Formatters) {
-		f.errors = formatFromString(strings.TrimRight(format, "\n"))
+		f.formats[sev.clamp()] = fmtFunc
+		f.inline[sev.clamp()] = false
 	}
 }
 
+// FormatError specifies the format string for formatting errors.
+func FormatError(format string) FormatOption {
+	return FormatSeverity(SeverityError, format)
+}
+
 // FormatErrorFunc specifies the formatting function for formatting
 // errors.
 func FormatErrorFunc(fmtFunc FormatFunc) FormatOption {
-	return func(f *Formatters) {
-		f.errors = fmtFunc
-	}
+	return FormatSeverityFunc(SeverityError, fmtFunc)
 }
 
 // FormatWarning specifies the format string for formatting warnings.
 func FormatWarning(format string) FormatOption {
-	return func(f *Formatters) {
-		f.warnings = formatFromString(strings.TrimRight(format, "\n"))
-	}
+	return FormatSeverity(SeverityWarning, format)
 }
 
 // FormatWarningFunc specifies the formatting function for formatting
 // warnings.
 func FormatWarningFunc(fmtFunc FormatFunc) FormatOption {
+	return FormatSeverityFunc(SeverityWarning, fmtFunc)
+}
+
+// FormatCodes specifies whether the default format should include
+// the diagnostic code of the error, if it has one (see CodeOf).  When
+// enabled, the code is included in brackets following the severity,
+// e.g., "ERROR[CFG1003]: ".  This option does not alter formats set
+// by other options, such as FormatError.
+func FormatCodes(enable bool) FormatOption {
+	return func(f *Formatters) {
+		f.codes = enable
+	}
+}
+
+// FormatFields specifies whether the structured fields attached to
+// the error, if any (see Fields), should be appended to the formatted
+// message as space-separated key=value pairs, sorted by key.  Unlike
+// FormatCodes, this applies to all formats, including those set by
+// other options, such as FormatError.
+func FormatFields(enable bool) FormatOption {
 	return func(f *Formatters) {
-		f.warnings = fmtFunc
+		f.fields = enable
 	}
 }
 
 // NewFormatters constructs a new Formatters instance with the
-// specified options.  A reasonable default is used for both format
-// strings.
+// specified options.  A reasonable default is used for all format
+// strings; the default prefixes the message with the upper-cased
+// name of the severity, e.g., "ERROR: " or "WARNING: ".  If the error
+// has a position (see PositionOf), the default format also prefixes
+// the position, e.g., "file:line:col: ERROR: ", and if the error has
+// a scope (see Scope), the scope path follows the position, e.g.,
+// "deploy > services: ERROR: ".
 func NewFormatters(options ...FormatOption) *Formatters {
 	obj := &Formatters{
-		errors:   formatFromString("ERROR: %s"),
-		warnings: formatFromString("WARNING: %s"),
+		theme: DefaultColorTheme,
+	}
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		obj.formats[sev] = obj.formatDefault(sev)
 	}
 
 	// Apply options
@@ -92,12 +169,43 @@ func NewFormatters(options ...FormatOption) *Formatters {
 	return obj
 }
 
-// Format formats the specified error according to whether it is an
-// error or a warning.  It returns the formatted result.
+// Format formats the specified error according to its severity, as
+// determined by SeverityOf.  If enabled with FormatFields, any fields
+// attached to the error are appended.  If enabled with
+// FormatSnippets, a snippet of the source text at the position of the
+// error follows.  Any notes attached to the error (see Notes) are
+// emitted on subsequent lines, indented beneath the error.  (Formats
+// set by FormatGitHub or FormatTemplate include the fields and notes
+// in the formatted message instead.)  If enabled with FormatCatalog,
+// the message of an error constructed from a message ID is localized.
+// It returns the formatted result.
 func (f *Formatters) Format(err error) string {
-	if IsWarning(err) {
-		return f.warnings(err)
+	if f.catalog != nil {
+		err = f.catalog.localize(f.locale, err)
+	}
+
+	buf := &strings.Builder{}
+	sev := SeverityOf(err)
+	buf.WriteString(f.formats[sev](err))
+	if f.inline[sev] {
+		return buf.String()
+	}
+
+	// Add the fields
+	if fields := Fields(err); f.fields && len(fields) > 0 {
+		fmt.Fprintf(buf, " %s", joinFields(fields))
+	}
+
+	// Add the source snippet and the notes
+	notes := Notes(err)
+	if f.snippets != nil {
+		var snippet string
+		snippet, notes = f.snippets.render(err, notes)
+		buf.WriteString(snippet)
+	}
+	for _, note := range notes {
+		fmt.Fprintf(buf, "\n    %s", note)
 	}
 
-	return f.errors(err)
+	return buf.String()
 }
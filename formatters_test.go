@@ -31,6 +31,144 @@ func TestFormatFromString(t *testing.T) {
 	assert.Equal(t, "test: test error", result)
 }
 
+func TestFormattersFormatDefault(t *testing.T) {
+	obj := &Formatters{}
+
+	fmtFunc := obj.formatDefault(SeverityNotice)
+	result := fmtFunc(errors.New("test error")) //nolint:goerr113
+
+	assert.Equal(t, "NOTICE: test error", result)
+}
+
+func TestFormattersFormatDefaultPosition(t *testing.T) {
+	obj := &Formatters{}
+	err := ErrorAt(Position{File: "file.cfg", Line: 3, Column: 5}, "test error")
+
+	fmtFunc := obj.formatDefault(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "file.cfg:3:5: ERROR: test error", result)
+}
+
+func TestFormattersFormatDefaultUnknownPosition(t *testing.T) {
+	obj := &Formatters{}
+	err := WithPosition(NewWarning("test warning"), Position{})
+
+	fmtFunc := obj.formatDefault(SeverityWarning)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "WARNING: test warning", result)
+}
+
+func TestFormattersFormatDefaultScope(t *testing.T) {
+	obj := &Formatters{}
+	err := WithScope(WithScope(errors.New("test error"), "web"), "deploy", "services") //nolint:goerr113
+
+	fmtFunc := obj.formatDefault(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "deploy > services > web: ERROR: test error", result)
+}
+
+func TestFormattersFormatDefaultPositionScope(t *testing.T) {
+	obj := &Formatters{}
+	err := WithScope(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"), "deploy")
+
+	fmtFunc := obj.formatDefault(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "file.cfg:3: deploy: ERROR: test error", result)
+}
+
+func TestFormattersFormatDefaultCodeDisabled(t *testing.T) {
+	obj := &Formatters{}
+	err := WithCode(errors.New("test error"), "CFG1003") //nolint:goerr113
+
+	fmtFunc := obj.formatDefault(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "ERROR: test error", result)
+}
+
+func TestFormattersFormatDefaultCodeEnabled(t *testing.T) {
+	obj := &Formatters{
+		codes: true,
+	}
+	err := WithCode(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"), "CFG1003")
+
+	fmtFunc := obj.formatDefault(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "file.cfg:3: ERROR[CFG1003]: test error", result)
+}
+
+func TestFormattersFormatDefaultCodeMissing(t *testing.T) {
+	obj := &Formatters{
+		codes: true,
+	}
+
+	fmtFunc := obj.formatDefault(SeverityWarning)
+	result := fmtFunc(NewWarning("test warning"))
+
+	assert.Equal(t, "WARNING: test warning", result)
+}
+
+func TestFormatSeverity(t *testing.T) {
+	err := NewSeverity(SeverityNotice, "test notice")
+	obj := &Formatters{}
+
+	opt := FormatSeverity(SeverityNotice, "test: %s\n\n")
+	opt(obj)
+
+	require.NotNil(t, obj.formats[SeverityNotice])
+	result := obj.formats[SeverityNotice](err)
+	assert.Equal(t, "test: test notice", result)
+}
+
+func TestFormatSeverityFunc(t *testing.T) {
+	err := NewSeverity(SeverityDebug, "test debug")
+	fmtFuncCalled := false
+	fmtFunc := func(fErr error) string {
+		assert.Same(t, err, fErr)
+		fmtFuncCalled = true
+		return "formatted"
+	}
+	obj := &Formatters{}
+
+	opt := FormatSeverityFunc(SeverityDebug, fmtFunc)
+	opt(obj)
+
+	require.NotNil(t, obj.formats[SeverityDebug])
+	result := obj.formats[SeverityDebug](err)
+	assert.Equal(t, "formatted", result)
+	assert.True(t, fmtFuncCalled)
+}
+
+func TestFormatSeverityFuncClamped(t *testing.T) {
+	fmtFunc := func(fErr error) string {
+		return "formatted"
+	}
+	obj := &Formatters{}
+
+	opt := FormatSeverityFunc(SeverityFatal+5, fmtFunc)
+	opt(obj)
+
+	assert.NotNil(t, obj.formats[SeverityFatal])
+}
+
+func TestFormatSeverityFuncResetsInline(t *testing.T) {
+	fmtFunc := func(err error) string {
+		return "formatted"
+	}
+	obj := &Formatters{}
+	obj.inline[SeverityError] = true
+
+	opt := FormatSeverityFunc(SeverityError, fmtFunc)
+	opt(obj)
+
+	assert.False(t, obj.inline[SeverityError])
+}
+
 func TestFormatError(t *testing.T) {
 	err := errors.New("test error") //nolint:goerr113
 	obj := &Formatters{}
@@ -38,8 +176,8 @@ func TestFormatError(t *testing.T) {
 	opt := FormatError("test: %s\n\n")
 	opt(obj)
 
-	require.NotNil(t, obj.errors)
-	result := obj.errors(err)
+	require.NotNil(t, obj.formats[SeverityError])
+	result := obj.formats[SeverityError](err)
 	assert.Equal(t, "test: test error", result)
 }
 
@@ -56,8 +194,8 @@ func TestFormatErrorFunc(t *testing.T) {
 	opt := FormatErrorFunc(fmtFunc)
 	opt(obj)
 
-	require.NotNil(t, obj.errors)
-	result := obj.errors(err)
+	require.NotNil(t, obj.formats[SeverityError])
+	result := obj.formats[SeverityError](err)
 	assert.Equal(t, "formatted", result)
 	assert.True(t, fmtFuncCalled)
 }
@@ -69,8 +207,8 @@ func TestFormatWarning(t *testing.T) {
 	opt := FormatWarning("test: %s\n\n")
 	opt(obj)
 
-	require.NotNil(t, obj.warnings)
-	result := obj.warnings(err)
+	require.NotNil(t, obj.formats[SeverityWarning])
+	result := obj.formats[SeverityWarning](err)
 	assert.Equal(t, "test: test warning", result)
 }
 
@@ -87,12 +225,30 @@ func TestFormatWarningFunc(t *testing.T) {
 	opt := FormatWarningFunc(fmtFunc)
 	opt(obj)
 
-	require.NotNil(t, obj.warnings)
-	result := obj.warnings(err)
+	require.NotNil(t, obj.formats[SeverityWarning])
+	result := obj.formats[SeverityWarning](err)
 	assert.Equal(t, "formatted", result)
 	assert.True(t, fmtFuncCalled)
 }
 
+func TestFormatCodes(t *testing.T) {
+	obj := &Formatters{}
+
+	opt := FormatCodes(true)
+	opt(obj)
+
+	assert.True(t, obj.codes)
+}
+
+func TestFormatFields(t *testing.T) {
+	obj := &Formatters{}
+
+	opt := FormatFields(true)
+	opt(obj)
+
+	assert.True(t, obj.fields)
+}
+
 func TestNewFormatters(t *testing.T) {
 	var opt1Called *Formatters
 	var opt2Called *Formatters
@@ -107,19 +263,27 @@ func TestNewFormatters(t *testing.T) {
 
 	result := NewFormatters(options...)
 
-	require.NotNil(t, result.errors)
-	assert.Equal(t, "ERROR: test error", result.errors(errors.New("test error"))) //nolint:goerr113
-	require.NotNil(t, result.warnings)
-	assert.Equal(t, "WARNING: test warning", result.warnings(NewWarning("test warning")))
+	require.NotNil(t, result.formats[SeverityError])
+	assert.Equal(t, "ERROR: test error", result.formats[SeverityError](errors.New("test error"))) //nolint:goerr113
+	require.NotNil(t, result.formats[SeverityWarning])
+	assert.Equal(t, "WARNING: test warning", result.formats[SeverityWarning](NewWarning("test warning")))
+	require.NotNil(t, result.formats[SeverityDebug])
+	assert.Equal(t, "DEBUG: test debug", result.formats[SeverityDebug](NewSeverity(SeverityDebug, "test debug")))
+	require.NotNil(t, result.formats[SeverityInfo])
+	assert.Equal(t, "INFO: test info", result.formats[SeverityInfo](NewSeverity(SeverityInfo, "test info")))
+	require.NotNil(t, result.formats[SeverityNotice])
+	assert.Equal(t, "NOTICE: test notice", result.formats[SeverityNotice](NewSeverity(SeverityNotice, "test notice")))
+	require.NotNil(t, result.formats[SeverityFatal])
+	assert.Equal(t, "FATAL: test fatal", result.formats[SeverityFatal](NewSeverity(SeverityFatal, "test fatal")))
+	assert.Equal(t, DefaultColorTheme, result.theme)
 	assert.Same(t, result, opt1Called)
 	assert.Same(t, result, opt2Called)
 }
 
 func TestFormattersFormatWarning(t *testing.T) {
 	err := NewWarning("test warning")
-	obj := &Formatters{
-		warnings: formatFromString("WARNING: %s"),
-	}
+	obj := &Formatters{}
+	obj.formats[SeverityWarning] = formatFromString("WARNING: %s")
 
 	result := obj.Format(err)
 
@@ -128,11 +292,103 @@ func TestFormattersFormatWarning(t *testing.T) {
 
 func TestFormattersFormatError(t *testing.T) {
 	err := errors.New("test error") //nolint:goerr113
+	obj := &Formatters{}
+	obj.formats[SeverityError] = formatFromString("ERROR: %s")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "ERROR: test error", result)
+}
+
+func TestFormattersFormatSeverity(t *testing.T) {
+	err := NewSeverity(SeverityInfo, "test info")
+	obj := &Formatters{}
+	obj.formats[SeverityInfo] = formatFromString("INFO: %s")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "INFO: test info", result)
+}
+
+func TestFormattersFormatNotes(t *testing.T) {
+	err := WithNotes(
+		errors.New("test error"), //nolint:goerr113
+		NoteAt(Position{File: "file.cfg", Line: 3}, "previously defined here"),
+		Notef("keys must be unique"),
+	)
+	obj := &Formatters{}
+	obj.formats[SeverityError] = formatFromString("ERROR: %s")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "ERROR: test error\n    file.cfg:3: note: previously defined here\n    note: keys must be unique", result)
+}
+
+func TestFormattersFormatFieldsDisabled(t *testing.T) {
+	err := WithFields(errors.New("test error"), "rule", "no-dup") //nolint:goerr113
+	obj := &Formatters{}
+	obj.formats[SeverityError] = formatFromString("ERROR: %s")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "ERROR: test error", result)
+}
+
+func TestFormattersFormatFieldsEnabled(t *testing.T) {
+	err := WithNotes(
+		WithFields(errors.New("test error"), "rule", "no-dup", "object", "my object"), //nolint:goerr113
+		Notef("a note"),
+	)
 	obj := &Formatters{
-		errors: formatFromString("ERROR: %s"),
+		fields: true,
 	}
+	obj.formats[SeverityError] = formatFromString("ERROR: %s")
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "ERROR: test error object=\"my object\" rule=no-dup\n    note: a note", result)
+}
+
+func TestFormattersFormatInline(t *testing.T) {
+	err := WithNotes(
+		WithFields(errors.New("test error"), "rule", "no-dup"), //nolint:goerr113
+		Notef("a note"),
+	)
+	obj := &Formatters{
+		fields: true,
+	}
+	obj.formats[SeverityError] = formatFromString("ERROR: %s")
+	obj.inline[SeverityError] = true
 
 	result := obj.Format(err)
 
 	assert.Equal(t, "ERROR: test error", result)
 }
+
+func TestFormattersFormatSnippets(t *testing.T) {
+	err := WithNotes(
+		ErrorAt(Position{File: "file.cfg", Line: 2, Column: 1, End: &Position{Line: 2, Column: 2}}, "test error"),
+		NoteAt(Position{File: "file.cfg", Line: 1, Column: 1}, "previously defined here"),
+		Notef("keys must be unique"),
+	)
+	obj := NewFormatters(FormatSnippets(SourceMap{"file.cfg": []byte("a = 1\na = 2\n")}))
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "file.cfg:2:1: ERROR: test error\n  |\n1 | a = 1\n  | - previously defined here\n2 | a = 2\n  | ^\n    note: keys must be unique", result)
+}
+
+func TestFormattersFormatCatalog(t *testing.T) {
+	cat := NewCatalog("en")
+	cat.Add("en", map[string]string{"dup": "duplicate key %q"})
+	cat.Add("fr", map[string]string{"dup": "clé en double %q"})
+	err := WithNotes(
+		WithPosition(SeverityWrap(SeverityWarning, cat.New("dup", "foo")), Position{File: "file.cfg", Line: 3}),
+		Notef("a note"),
+	)
+	obj := NewFormatters(FormatCatalog(cat, "fr_FR"))
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "file.cfg:3: WARNING: clé en double \"foo\"\n    note: a note", result)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// githubDataEscaper escapes the message of a GitHub Actions workflow
+// command.
+var githubDataEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+)
+
+// githubPropertyEscaper escapes the property values of a GitHub
+// Actions workflow command.
+var githubPropertyEscaper = strings.NewReplacer(
+	"%", "%25",
+	"\r", "%0D",
+	"\n", "%0A",
+	":", "%3A",
+	",", "%2C",
+)
+
+// githubCommand maps a severity to the name of the GitHub Actions
+// workflow command used to annotate it.
+func githubCommand(sev Severity) string {
+	switch {
+	case sev >= SeverityError:
+		return "error"
+	case sev == SeverityWarning:
+		return "warning"
+	case sev >= SeverityInfo:
+		return "notice"
+	}
+
+	return "debug"
+}
+
+// formatGitHub constructs the FormatFunc used by FormatGitHub for a
+// severity.  The message is prefixed with the scope path, if there is
+// one, and is followed by the fields, if enabled with FormatFields,
+// and any notes, each on its own line.  The position of the error,
+// if known, and its diagnostic code are emitted as properties of the
+// command.
+func (f *Formatters) formatGitHub(sev Severity) FormatFunc {
+	cmd := githubCommand(sev)
+
+	return func(err error) string {
+		// Construct the message
+		msg := &strings.Builder{}
+		if scope := Scope(err); len(scope) > 0 {
+			fmt.Fprintf(msg, "%s: ", joinScope(scope))
+		}
+		msg.WriteString(err.Error())
+		if fields := Fields(err); f.fields && len(fields) > 0 {
+			fmt.Fprintf(msg, " %s", joinFields(fields))
+		}
+		for _, note := range Notes(err) {
+			fmt.Fprintf(msg, "\n%s", note)
+		}
+
+		// Debug messages accept no properties
+		if cmd == "debug" {
+			return fmt.Sprintf("::debug::%s", githubDataEscaper.Replace(msg.String()))
+		}
+
+		// Construct the properties
+		props := []string{}
+		if pos, ok := PositionOf(err); ok && pos.File != "" {
+			props = append(props, "file="+githubPropertyEscaper.Replace(pos.File))
+			if pos.IsValid() {
+				props = append(props, fmt.Sprintf("line=%d", pos.Line))
+				if pos.Column > 0 {
+					props = append(props, fmt.Sprintf("col=%d", pos.Column))
+				}
+				if pos.End != nil && pos.End.IsValid() {
+					props = append(props, fmt.Sprintf("endLine=%d", pos.End.Line))
+					if pos.End.Column > 0 {
+						props = append(props, fmt.Sprintf("endColumn=%d", pos.End.Column))
+					}
+				}
+			}
+		}
+		if code, ok := CodeOf(err); ok {
+			props = append(props, "title="+githubPropertyEscaper.Replace(code))
+		}
+
+		buf := &strings.Builder{}
+		fmt.Fprintf(buf, "::%s", cmd)
+		if len(props) > 0 {
+			fmt.Fprintf(buf, " %s", strings.Join(props, ","))
+		}
+		fmt.Fprintf(buf, "::%s", githubDataEscaper.Replace(msg.String()))
+
+		return buf.String()
+	}
+}
+
+// FormatGitHub specifies that errors should be formatted as GitHub
+// Actions workflow commands, e.g., "::error file=app.cfg,line=3,
+// col=5,title=CFG1003::message", so that they are displayed as
+// annotations when emitted to the output of a workflow step.  Errors
+// are annotated as errors, warnings as warnings, and errors of lesser
+// severity as notices; debugging messages are emitted with the
+// "::debug::" command.  The position of the error, if one is known,
+// is used for the file, line, and column properties, and the
+// diagnostic code, if there is one, is used as the title; errors
+// without a position produce plain annotations.  Fields, if enabled
+// with FormatFields, and notes are included in the message, which is
+// escaped as required by GitHub Actions.  This option replaces the
+// formats for all severities; it may be followed by other options,
+// such as FormatError, to override the format for a particular
+// severity.
+func FormatGitHub() FormatOption {
+	return func(f *Formatters) {
+		for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+			f.formats[sev] = f.formatGitHub(sev)
+			f.inline[sev] = true
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubDataEscaper(t *testing.T) {
+	result := githubDataEscaper.Replace("100% done\r\nkey: a,b")
+
+	assert.Equal(t, "100%25 done%0D%0Akey: a,b", result)
+}
+
+func TestGitHubPropertyEscaper(t *testing.T) {
+	result := githubPropertyEscaper.Replace("100% done\r\nkey: a,b")
+
+	assert.Equal(t, "100%25 done%0D%0Akey%3A a%2Cb", result)
+}
+
+func TestGitHubCommand(t *testing.T) {
+	assert.Equal(t, "debug", githubCommand(SeverityDebug))
+	assert.Equal(t, "notice", githubCommand(SeverityInfo))
+	assert.Equal(t, "notice", githubCommand(SeverityNotice))
+	assert.Equal(t, "warning", githubCommand(SeverityWarning))
+	assert.Equal(t, "error", githubCommand(SeverityError))
+	assert.Equal(t, "error", githubCommand(SeverityFatal))
+}
+
+func TestFormattersFormatGitHubPlain(t *testing.T) {
+	obj := &Formatters{}
+
+	fmtFunc := obj.formatGitHub(SeverityError)
+	result := fmtFunc(errors.New("test error")) //nolint:goerr113
+
+	assert.Equal(t, "::error::test error", result)
+}
+
+func TestFormattersFormatGitHubPosition(t *testing.T) {
+	obj := &Formatters{}
+	err := WithCode(ErrorAt(Position{
+		File:   "dir/app,1.cfg",
+		Line:   3,
+		Column: 5,
+		End:    &Position{Line: 4, Column: 2},
+	}, "test error"), "CFG1003")
+
+	fmtFunc := obj.formatGitHub(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "::error file=dir/app%2C1.cfg,line=3,col=5,endLine=4,endColumn=2,title=CFG1003::test error", result)
+}
+
+func TestFormattersFormatGitHubFileOnly(t *testing.T) {
+	obj := &Formatters{}
+	err := WarningAt(Position{File: "app.cfg"}, "test warning")
+
+	fmtFunc := obj.formatGitHub(SeverityWarning)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "::warning file=app.cfg::test warning", result)
+}
+
+func TestFormattersFormatGitHubNoFile(t *testing.T) {
+	obj := &Formatters{}
+	err := WithCode(WithPosition(NewWarning("test warning"), Position{Line: 3}), "CFG1003")
+
+	fmtFunc := obj.formatGitHub(SeverityWarning)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "::warning title=CFG1003::test warning", result)
+}
+
+func TestFormattersFormatGitHubDebug(t *testing.T) {
+	obj := &Formatters{}
+	err := WithCode(ErrorAt(Position{File: "app.cfg", Line: 3}, "test\ndebug"), "CFG1003")
+
+	fmtFunc := obj.formatGitHub(SeverityDebug)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "::debug::test%0Adebug", result)
+}
+
+func TestFormattersFormatGitHubDetails(t *testing.T) {
+	obj := &Formatters{
+		fields: true,
+	}
+	err := WithScope(WithNotes(
+		WithFields(errors.New("100% broken"), "rule", "no-dup"), //nolint:goerr113
+		NoteAt(Position{File: "app.cfg", Line: 1}, "previously defined here"),
+	), "deploy")
+
+	fmtFunc := obj.formatGitHub(SeverityError)
+	result := fmtFunc(err)
+
+	assert.Equal(t, "::error::deploy: 100%25 broken rule=no-dup%0Aapp.cfg:1: note: previously defined here", result)
+}
+
+func TestFormatGitHub(t *testing.T) {
+	obj := &Formatters{}
+
+	opt := FormatGitHub()
+	opt(obj)
+
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		assert.NotNil(t, obj.formats[sev])
+		assert.True(t, obj.inline[sev])
+	}
+}
+
+func TestFormatGitHubFormat(t *testing.T) {
+	obj := NewFormatters(FormatGitHub(), FormatFields(true))
+	err := WithNotes(
+		WithFields(WarningAt(Position{File: "app.cfg", Line: 3}, "test warning"), "rule", "no-dup"),
+		Notef("a note"),
+	)
+
+	result := obj.Format(err)
+
+	assert.Equal(t, "::warning file=app.cfg,line=3::test warning rule=no-dup%0Anote: a note", result)
+}
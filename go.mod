@@ -1,6 +1,6 @@
 module github.com/klmitch/kent
 
-go 1.17
+go 1.20
 
 require (
 	github.com/klmitch/patcher v1.1.0
@@ -0,0 +1,398 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JSONSchemaVersion is the version of the schema of the records
+// emitted by JSONReporter.  The version is incremented whenever an
+// incompatible change is made to the schema; fields may be added
+// without incrementing the version.
+const JSONSchemaVersion = 1
+
+// ErrUnsupportedVersion is returned by JSONDecoder.Decode if a record
+// has a schema version that is not supported.
+var ErrUnsupportedVersion = errors.New("unsupported JSON schema version")
+
+// JSONPosition is the representation of a Position in a JSONRecord.
+type JSONPosition struct {
+	File   string        `json:"file,omitempty"`   // File name
+	Line   int           `json:"line,omitempty"`   // Line number
+	Column int           `json:"column,omitempty"` // Column number
+	Offset int           `json:"offset,omitempty"` // Byte offset
+	End    *JSONPosition `json:"end,omitempty"`    // End of range
+}
+
+// JSONNote is the representation of a Note in a JSONRecord.
+type JSONNote struct {
+	Message  string        `json:"message"`            // Note text
+	Position *JSONPosition `json:"position,omitempty"` // Note position
+}
+
+// JSONTextEdit is the representation of a TextEdit in a JSONRecord.
+type JSONTextEdit struct {
+	File    string `json:"file"`     // File name
+	Start   int    `json:"start"`    // Start byte offset
+	End     int    `json:"end"`      // End byte offset
+	NewText string `json:"new_text"` // Replacement text
+}
+
+// JSONSuggestion is the representation of a Suggestion in a
+// JSONRecord.
+type JSONSuggestion struct {
+	Description string         `json:"description,omitempty"` // Description
+	Edits       []JSONTextEdit `json:"edits"`                 // Edits
+}
+
+// JSONRecord is a single record emitted by JSONReporter, describing a
+// single reported error.  Only the Version, Severity, and Message
+// fields are always present.
+type JSONRecord struct {
+	Version     int                    `json:"version"`                // Schema version
+	Severity    Severity               `json:"severity"`               // Severity name
+	Message     string                 `json:"message"`                // Error message
+	Chain       []string               `json:"chain,omitempty"`        // Wrapped messages
+	Position    *JSONPosition          `json:"position,omitempty"`     // Position
+	Code        string                 `json:"code,omitempty"`         // Diagnostic code
+	Scope       []string               `json:"scope,omitempty"`        // Scope path
+	Fields      map[string]interface{} `json:"fields,omitempty"`       // Structured fields
+	Notes       []JSONNote             `json:"notes,omitempty"`        // Related notes
+	Suggestions []JSONSuggestion       `json:"suggestions,omitempty"`  // Suggested fixes
+	MessageID   string                 `json:"message_id,omitempty"`   // Message ID
+	MessageArgs []interface{}          `json:"message_args,omitempty"` // Message arguments
+}
+
+// newJSONPosition is a helper that converts a Position into a
+// JSONPosition.  It returns nil if nothing is known about the
+// position.
+func newJSONPosition(pos Position) *JSONPosition {
+	if !pos.known() {
+		return nil
+	}
+
+	obj := &JSONPosition{
+		File:   pos.File,
+		Line:   pos.Line,
+		Column: pos.Column,
+		Offset: pos.Offset,
+	}
+	if pos.End != nil {
+		obj.End = newJSONPosition(*pos.End)
+	}
+
+	return obj
+}
+
+// position converts a JSONPosition back into a Position.
+func (jp *JSONPosition) position() Position {
+	if jp == nil {
+		return Position{}
+	}
+
+	pos := Position{
+		File:   jp.File,
+		Line:   jp.Line,
+		Column: jp.Column,
+		Offset: jp.Offset,
+	}
+	if jp.End != nil {
+		end := jp.End.position()
+		pos.End = &end
+	}
+
+	return pos
+}
+
+// chainOf is a helper that collects the distinct messages of the
+// errors wrapped by an error, in depth-first order.  Errors with the
+// same message as the error preceding them in the walk, such as
+// those attaching positions or codes, are skipped.
+func chainOf(err error) []string {
+	var chain []string
+	last := err.Error()
+	walk(err, func(e error) bool {
+		if msg := e.Error(); msg != last {
+			chain = append(chain, msg)
+			last = msg
+		}
+
+		return false
+	})
+
+	return chain
+}
+
+// jsonFields is a helper that prepares structured fields for
+// encoding.  Values that cannot be encoded as JSON are converted to
+// strings using fmt.Sprint.
+func jsonFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if _, err := json.Marshal(value); err != nil {
+			value = fmt.Sprint(value)
+		}
+		result[key] = value
+	}
+
+	return result
+}
+
+// jsonArgs is a helper that prepares message arguments for encoding.
+// Errors are converted to their messages, and other values that
+// cannot be encoded as JSON are converted to strings using
+// fmt.Sprint.
+func jsonArgs(args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = err.Error()
+		} else if _, err := json.Marshal(arg); err != nil {
+			arg = fmt.Sprint(arg)
+		}
+		result[i] = arg
+	}
+
+	return result
+}
+
+// NewJSONRecord constructs a JSONRecord describing an error.  All
+// the information available from the error, such as its position,
+// diagnostic code, scope, structured fields, notes, suggested fixes,
+// and message ID and arguments, is included in the record.
+func NewJSONRecord(err error) *JSONRecord {
+	obj := &JSONRecord{
+		Version:  JSONSchemaVersion,
+		Severity: SeverityOf(err),
+		Message:  err.Error(),
+		Chain:    chainOf(err),
+		Scope:    Scope(err),
+		Fields:   jsonFields(Fields(err)),
+	}
+
+	if pos, ok := PositionOf(err); ok {
+		obj.Position = newJSONPosition(pos)
+	}
+	obj.Code, _ = CodeOf(err)
+	if id, args, ok := MessageOf(err); ok {
+		obj.MessageID = id
+		obj.MessageArgs = jsonArgs(args)
+	}
+
+	for _, note := range Notes(err) {
+		obj.Notes = append(obj.Notes, JSONNote{
+			Message:  note.Message,
+			Position: newJSONPosition(note.Position),
+		})
+	}
+
+	for _, sug := range Suggestions(err) {
+		jsug := JSONSuggestion{
+			Description: sug.Description,
+			Edits:       make([]JSONTextEdit, len(sug.Edits)),
+		}
+		for i, edit := range sug.Edits {
+			jsug.Edits[i] = JSONTextEdit(edit)
+		}
+		obj.Suggestions = append(obj.Suggestions, jsug)
+	}
+
+	return obj
+}
+
+// chainError is an error reconstructed from the Chain field of a
+// JSONRecord.
+type chainError struct {
+	msg string // The error message
+	err error  // The wrapped error
+}
+
+// Error returns the error message.
+func (ce *chainError) Error() string {
+	return ce.msg
+}
+
+// Unwrap returns the wrapped error, if there is one.
+func (ce *chainError) Unwrap() error {
+	return ce.err
+}
+
+// Err reconstructs an error from the record.  The returned error has
+// the severity, position, diagnostic code, scope, fields, notes,
+// suggested fixes, and message ID and arguments described by the
+// record, all of which may be retrieved using the usual helpers, such
+// as SeverityOf, IsWarning, and PositionOf; the messages in the chain
+// are reconstructed as wrapped errors.  Note that numeric field
+// values and message arguments will be float64 values after decoding.
+func (r *JSONRecord) Err() error {
+	// Reconstruct the chain
+	var err error
+	for i := len(r.Chain) - 1; i >= 0; i-- {
+		err = &chainError{
+			msg: r.Chain[i],
+			err: err,
+		}
+	}
+	err = &severityError{
+		sev: r.Severity,
+		msg: r.Message,
+		err: err,
+	}
+	if r.MessageID != "" {
+		err = &messageError{
+			id:   r.MessageID,
+			args: r.MessageArgs,
+			err:  err,
+		}
+	}
+
+	// Attach the additional information
+	if len(r.Suggestions) > 0 {
+		sugs := make([]Suggestion, len(r.Suggestions))
+		for i, jsug := range r.Suggestions {
+			sugs[i].Description = jsug.Description
+			for _, edit := range jsug.Edits {
+				sugs[i].Edits = append(sugs[i].Edits, TextEdit(edit))
+			}
+		}
+		err = WithSuggestions(err, sugs...)
+	}
+	if len(r.Notes) > 0 {
+		notes := make([]Note, len(r.Notes))
+		for i, jnote := range r.Notes {
+			notes[i] = Note{
+				Message:  jnote.Message,
+				Position: jnote.Position.position(),
+			}
+		}
+		err = WithNotes(err, notes...)
+	}
+	if len(r.Fields) > 0 {
+		err = &fieldsError{
+			fields: r.Fields,
+			err:    err,
+		}
+	}
+	if len(r.Scope) > 0 {
+		err = WithScope(err, r.Scope...)
+	}
+	if r.Code != "" {
+		err = WithCode(err, r.Code)
+	}
+	if r.Position != nil {
+		err = WithPosition(err, r.Position.position())
+	}
+
+	return err
+}
+
+// JSONReporter is a Reporter that emits errors and warnings to a
+// specified io.Writer stream in the JSON Lines format, with one
+// JSONRecord per line.
+type JSONReporter struct {
+	sync.Mutex
+
+	enc *json.Encoder // The encoder for the output stream
+	rep Reporter      // Child reporter
+}
+
+// NewJSONReporter constructs a new JSON reporter.  A JSON reporter
+// emits a JSONRecord describing each reported error to the specified
+// output stream, one record per line.  The stream may be read back
+// using a JSONDecoder.
+func NewJSONReporter(out io.Writer, rep Reporter) *JSONReporter {
+	return &JSONReporter{
+		enc: json.NewEncoder(out),
+		rep: rep,
+	}
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (jr *JSONReporter) Report(err error) {
+	record := NewJSONRecord(err)
+
+	jr.Lock()
+	_ = jr.enc.Encode(record)
+	jr.Unlock()
+
+	jr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (jr *JSONReporter) Unwrap() []Reporter {
+	return []Reporter{jr.rep}
+}
+
+// JSONDecoder reads a stream of records emitted by JSONReporter.
+type JSONDecoder struct {
+	dec *json.Decoder // The decoder for the input stream
+}
+
+// NewJSONDecoder constructs a new JSONDecoder reading from the
+// specified input stream.
+func NewJSONDecoder(in io.Reader) *JSONDecoder {
+	return &JSONDecoder{
+		dec: json.NewDecoder(in),
+	}
+}
+
+// DecodeRecord reads the next record from the input stream.  At the
+// end of the stream, io.EOF is returned.  If the record has an
+// unsupported schema version, an error wrapping ErrUnsupportedVersion
+// is returned.
+func (jd *JSONDecoder) DecodeRecord() (*JSONRecord, error) {
+	record := &JSONRecord{}
+	if err := jd.dec.Decode(record); err != nil {
+		return nil, err
+	}
+
+	if record.Version < 1 || record.Version > JSONSchemaVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, record.Version)
+	}
+
+	return record, nil
+}
+
+// Decode reads the next record from the input stream and stores the
+// error it describes, as reconstructed by JSONRecord.Err, in the
+// value pointed to by diag.  At the end of the stream, io.EOF is
+// returned.
+func (jd *JSONDecoder) Decode(diag *error) error {
+	record, err := jd.DecodeRecord()
+	if err != nil {
+		return err
+	}
+
+	*diag = record.Err()
+
+	return nil
+}
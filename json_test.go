@@ -0,0 +1,395 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONPositionUnknown(t *testing.T) {
+	result := newJSONPosition(Position{})
+
+	assert.Nil(t, result)
+}
+
+func TestNewJSONPositionKnown(t *testing.T) {
+	result := newJSONPosition(Position{
+		File:   "file.cfg",
+		Line:   3,
+		Column: 5,
+		Offset: 42,
+		End:    &Position{File: "file.cfg", Line: 3, Column: 8, Offset: 45},
+	})
+
+	assert.Equal(t, &JSONPosition{
+		File:   "file.cfg",
+		Line:   3,
+		Column: 5,
+		Offset: 42,
+		End:    &JSONPosition{File: "file.cfg", Line: 3, Column: 8, Offset: 45},
+	}, result)
+}
+
+func TestJSONPositionPositionNil(t *testing.T) {
+	var obj *JSONPosition
+
+	result := obj.position()
+
+	assert.Equal(t, Position{}, result)
+}
+
+func TestJSONPositionPosition(t *testing.T) {
+	obj := &JSONPosition{
+		File:   "file.cfg",
+		Line:   3,
+		Column: 5,
+		Offset: 42,
+		End:    &JSONPosition{File: "file.cfg", Line: 3, Column: 8, Offset: 45},
+	}
+
+	result := obj.position()
+
+	assert.Equal(t, Position{
+		File:   "file.cfg",
+		Line:   3,
+		Column: 5,
+		Offset: 42,
+		End:    &Position{File: "file.cfg", Line: 3, Column: 8, Offset: 45},
+	}, result)
+}
+
+func TestChainOf(t *testing.T) {
+	inner := errors.New("inner") //nolint:goerr113
+	err := WithPosition(fmt.Errorf("middle: %w", WithCode(inner, "CFG1003")), Position{Line: 3})
+
+	result := chainOf(err)
+
+	assert.Equal(t, []string{"inner"}, result)
+}
+
+func TestChainOfNone(t *testing.T) {
+	result := chainOf(NewWarning("warning"))
+
+	assert.Nil(t, result)
+}
+
+func TestJSONFields(t *testing.T) {
+	result := jsonFields(map[string]interface{}{
+		"good": 42,
+		"bad":  make(chan int),
+	})
+
+	assert.Equal(t, 42, result["good"])
+	assert.IsType(t, "", result["bad"])
+}
+
+func TestJSONFieldsEmpty(t *testing.T) {
+	result := jsonFields(nil)
+
+	assert.Nil(t, result)
+}
+
+func TestJSONArgs(t *testing.T) {
+	result := jsonArgs([]interface{}{42, assert.AnError, make(chan int)})
+
+	assert.Equal(t, 42, result[0])
+	assert.Equal(t, assert.AnError.Error(), result[1])
+	assert.IsType(t, "", result[2])
+}
+
+func TestJSONArgsEmpty(t *testing.T) {
+	result := jsonArgs(nil)
+
+	assert.Nil(t, result)
+}
+
+func TestNewJSONRecordSimple(t *testing.T) {
+	result := NewJSONRecord(NewWarning("test warning"))
+
+	assert.Equal(t, &JSONRecord{
+		Version:  JSONSchemaVersion,
+		Severity: SeverityWarning,
+		Message:  "test warning",
+	}, result)
+}
+
+// fullDiag is a helper that constructs a diagnostic with all possible
+// additional information.
+func fullDiag() error {
+	err := Warningf("key %q: %w", "foo", errors.New("duplicate key")) //nolint:goerr113
+	err = WithSuggestions(err, Suggestion{
+		Description: "remove it",
+		Edits:       []TextEdit{{File: "file.cfg", Start: 10, End: 20}},
+	})
+	err = WithNotes(err, NoteAt(Position{File: "file.cfg", Line: 1}, "previously defined here"), Notef("keys must be unique"))
+	err = WithFields(err, "rule", "no-dup")
+	err = WithScope(err, "deploy", "services")
+	err = WithCode(err, "CFG1003")
+
+	return WithPosition(err, Position{File: "file.cfg", Line: 3, Column: 5})
+}
+
+func TestNewJSONRecordFull(t *testing.T) {
+	result := NewJSONRecord(fullDiag())
+
+	assert.Equal(t, &JSONRecord{
+		Version:  JSONSchemaVersion,
+		Severity: SeverityWarning,
+		Message:  `key "foo": duplicate key`,
+		Chain:    []string{"duplicate key"},
+		Position: &JSONPosition{File: "file.cfg", Line: 3, Column: 5},
+		Code:     "CFG1003",
+		Scope:    []string{"deploy", "services"},
+		Fields:   map[string]interface{}{"rule": "no-dup"},
+		Notes: []JSONNote{
+			{Message: "previously defined here", Position: &JSONPosition{File: "file.cfg", Line: 1}},
+			{Message: "keys must be unique"},
+		},
+		Suggestions: []JSONSuggestion{
+			{
+				Description: "remove it",
+				Edits:       []JSONTextEdit{{File: "file.cfg", Start: 10, End: 20}},
+			},
+		},
+	}, result)
+}
+
+func TestJSONRecordErrSimple(t *testing.T) {
+	obj := &JSONRecord{
+		Version:  JSONSchemaVersion,
+		Severity: SeverityNotice,
+		Message:  "test notice",
+	}
+
+	result := obj.Err()
+
+	assert.Equal(t, &severityError{
+		sev: SeverityNotice,
+		msg: "test notice",
+	}, result)
+}
+
+func TestJSONRecordErrFull(t *testing.T) {
+	obj := NewJSONRecord(fullDiag())
+
+	result := obj.Err()
+
+	assert.Equal(t, `key "foo": duplicate key`, result.Error())
+	assert.True(t, IsWarning(result))
+	assert.Equal(t, []string{"duplicate key"}, chainOf(result))
+	pos, ok := PositionOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, Position{File: "file.cfg", Line: 3, Column: 5}, pos)
+	code, ok := CodeOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, "CFG1003", code)
+	assert.Equal(t, []string{"deploy", "services"}, Scope(result))
+	assert.Equal(t, map[string]interface{}{"rule": "no-dup"}, Fields(result))
+	assert.Equal(t, []Note{
+		NoteAt(Position{File: "file.cfg", Line: 1}, "previously defined here"),
+		Notef("keys must be unique"),
+	}, Notes(result))
+	assert.Equal(t, []Suggestion{
+		{
+			Description: "remove it",
+			Edits:       []TextEdit{{File: "file.cfg", Start: 10, End: 20}},
+		},
+	}, Suggestions(result))
+}
+
+func TestNewJSONRecordMessage(t *testing.T) {
+	cat := NewCatalog("en")
+	cat.Add("en", map[string]string{"dup": "duplicate key %q on line %d"})
+
+	result := NewJSONRecord(cat.New("dup", "foo", 3))
+
+	assert.Equal(t, &JSONRecord{
+		Version:     JSONSchemaVersion,
+		Severity:    SeverityError,
+		Message:     `duplicate key "foo" on line 3`,
+		MessageID:   "dup",
+		MessageArgs: []interface{}{"foo", 3},
+	}, result)
+}
+
+func TestJSONRecordErrMessage(t *testing.T) {
+	obj := &JSONRecord{
+		Version:     JSONSchemaVersion,
+		Severity:    SeverityWarning,
+		Message:     `duplicate key "foo"`,
+		MessageID:   "dup",
+		MessageArgs: []interface{}{"foo"},
+	}
+
+	result := obj.Err()
+
+	assert.Equal(t, `duplicate key "foo"`, result.Error())
+	assert.True(t, IsWarning(result))
+	id, args, ok := MessageOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, "dup", id)
+	assert.Equal(t, []interface{}{"foo"}, args)
+}
+
+func TestChainErrorError(t *testing.T) {
+	obj := &chainError{
+		msg: "some message",
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, "some message", result)
+}
+
+func TestChainErrorUnwrap(t *testing.T) {
+	obj := &chainError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestJSONReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &JSONReporter{})
+}
+
+func TestNewJSONReporter(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+
+	result := NewJSONReporter(out, rep)
+
+	assert.Equal(t, &JSONReporter{
+		enc: json.NewEncoder(out),
+		rep: rep,
+	}, result)
+}
+
+func TestJSONReporterReport(t *testing.T) {
+	err := WithCode(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"), "CFG1003")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	out := &bytes.Buffer{}
+	obj := &JSONReporter{
+		enc: json.NewEncoder(out),
+		rep: rep,
+	}
+
+	obj.Report(err)
+
+	assert.Equal(t, `{"version":1,"severity":"error","message":"test error","position":{"file":"file.cfg","line":3},"code":"CFG1003"}`+"\n", out.String())
+	rep.AssertExpectations(t)
+}
+
+func TestJSONReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &JSONReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestNewJSONDecoder(t *testing.T) {
+	in := &bytes.Buffer{}
+
+	result := NewJSONDecoder(in)
+
+	assert.Equal(t, &JSONDecoder{
+		dec: json.NewDecoder(in),
+	}, result)
+}
+
+func TestJSONDecoderDecodeRecord(t *testing.T) {
+	obj := NewJSONDecoder(strings.NewReader(`{"version":1,"severity":"warning","message":"test warning"}`))
+
+	result, err := obj.DecodeRecord()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &JSONRecord{
+		Version:  1,
+		Severity: SeverityWarning,
+		Message:  "test warning",
+	}, result)
+}
+
+func TestJSONDecoderDecodeRecordBadVersion(t *testing.T) {
+	obj := NewJSONDecoder(strings.NewReader(`{"version":2,"severity":"warning","message":"test warning"}`))
+
+	result, err := obj.DecodeRecord()
+
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+	assert.Nil(t, result)
+}
+
+func TestJSONDecoderDecodeRecordBadSeverity(t *testing.T) {
+	obj := NewJSONDecoder(strings.NewReader(`{"version":1,"severity":"bogus","message":"test warning"}`))
+
+	result, err := obj.DecodeRecord()
+
+	assert.True(t, errors.Is(err, ErrUnknownSeverity))
+	assert.Nil(t, result)
+}
+
+func TestJSONDecoderDecodeEOF(t *testing.T) {
+	obj := NewJSONDecoder(strings.NewReader(""))
+	var diag error
+
+	err := obj.Decode(&diag)
+
+	assert.Same(t, io.EOF, err)
+	assert.Nil(t, diag)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rep := NewJSONReporter(buf, Root())
+	rep.Report(fullDiag())
+	rep.Report(assert.AnError)
+	rep.Report(NewSeverity(SeverityInfo, "test info"))
+	obj := NewJSONDecoder(buf)
+	diags := []error{}
+
+	for {
+		var diag error
+		err := obj.Decode(&diag)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		diags = append(diags, diag)
+	}
+
+	require.Len(t, diags, 3)
+	assert.True(t, IsWarning(diags[0]))
+	assert.Equal(t, `key "foo": duplicate key`, diags[0].Error())
+	assert.False(t, IsWarning(diags[1]))
+	assert.Equal(t, SeverityError, SeverityOf(diags[1]))
+	assert.Equal(t, assert.AnError.Error(), diags[1].Error())
+	assert.Equal(t, SeverityInfo, SeverityOf(diags[2]))
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"sync"
+)
+
+// junitUngrouped is the name of the test case used for errors for
+// which the grouping function returns an empty string.
+const junitUngrouped = "(ungrouped)"
+
+// junitMessage describes a JUnit failure or skipped element.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junitTestCase describes a JUnit testcase element.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitTestSuite describes a JUnit testsuite element.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestSuites describes the top-level JUnit testsuites element.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// JUnitGroupFunc describes a function that determines the checked
+// unit, such as a file, that an error belongs to.  It will be passed
+// the error and must return the name of the unit.
+type JUnitGroupFunc func(err error) string
+
+// JUnitGroupDefault is the default JUnitGroupFunc.  It groups errors
+// by the file of their position, if one is known (see PositionOf),
+// and otherwise by their scope path (see Scope).
+func JUnitGroupDefault(err error) string {
+	if pos, ok := PositionOf(err); ok && pos.File != "" {
+		return pos.File
+	}
+
+	return joinScope(Scope(err))
+}
+
+// JUnitReporter is a Reporter that accumulates the errors and
+// warnings reported using it, and writes them to a specified
+// io.Writer stream as a JUnit XML document when it is closed.
+type JUnitReporter struct {
+	sync.Mutex
+
+	out    io.Writer          // The output stream to write to
+	suite  string             // The name of the test suite
+	group  JUnitGroupFunc     // Function to determine the unit
+	format *Formatters        // Formatters to use
+	units  []string           // The units, in order of appearance
+	diags  map[string][]error // The reported errors, by unit
+	closed bool               // Set when the document has been written
+	rep    Reporter           // Child reporter
+}
+
+// JUnitReporterOption describes an option for a JUnitReporter.
+type JUnitReporterOption func(*JUnitReporter)
+
+// JUnitSuite specifies the name of the test suite in the JUnit XML
+// document.  The name is also used as the class name of each test
+// case.  The default suite name is "kent".
+func JUnitSuite(name string) JUnitReporterOption {
+	return func(jr *JUnitReporter) {
+		jr.suite = name
+	}
+}
+
+// JUnitGroupBy specifies the function used to determine the checked
+// unit each error belongs to; each unit becomes a test case in the
+// JUnit XML document.  Errors for which the function returns an
+// empty string are grouped into a test case named "(ungrouped)".
+// The default is JUnitGroupDefault.
+func JUnitGroupBy(group JUnitGroupFunc) JUnitReporterOption {
+	return func(jr *JUnitReporter) {
+		jr.group = group
+	}
+}
+
+// JUnitFormat specifies the formatting options used to format the
+// errors included in the JUnit XML document.  By default, the
+// default formats are used; see NewFormatters.
+func JUnitFormat(formatOptions ...FormatOption) JUnitReporterOption {
+	return func(jr *JUnitReporter) {
+		jr.format = newFormatters(formatOptions...)
+	}
+}
+
+// NewJUnitReporter constructs a new JUnit reporter.  A JUnit reporter
+// accumulates the reported errors and warnings, grouped by the
+// checked unit they belong to, and writes a JUnit XML document to the
+// specified output stream when its Close method is called, such as
+// by the Close helper.  Each unit becomes a test case; units with
+// errors are reported as failures, and units with only warnings are
+// reported as skipped.  Units that were checked but had no errors
+// may be recorded using the Unit method, so that they are reported
+// as passing test cases.
+func NewJUnitReporter(out io.Writer, rep Reporter, options ...JUnitReporterOption) *JUnitReporter {
+	obj := &JUnitReporter{
+		out:   out,
+		suite: "kent",
+		group: JUnitGroupDefault,
+		diags: map[string][]error{},
+		rep:   rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	// Set the default formatters
+	if obj.format == nil {
+		obj.format = newFormatters()
+	}
+
+	return obj
+}
+
+// addUnit is a helper that adds a unit to the list of units, if it
+// has not already been added.  It must be called with the mutex held.
+func (jr *JUnitReporter) addUnit(name string) {
+	if _, ok := jr.diags[name]; !ok {
+		jr.units = append(jr.units, name)
+		jr.diags[name] = nil
+	}
+}
+
+// Unit records that the named unit was checked.  A unit that has no
+// errors reported for it is included in the JUnit XML document as a
+// passing test case.
+func (jr *JUnitReporter) Unit(name string) {
+	// Lock the mutex for thread safety
+	jr.Lock()
+	defer jr.Unlock()
+
+	jr.addUnit(name)
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (jr *JUnitReporter) Report(err error) {
+	name := jr.group(err)
+	if name == "" {
+		name = junitUngrouped
+	}
+
+	jr.Lock()
+	jr.addUnit(name)
+	jr.diags[name] = append(jr.diags[name], err)
+	jr.Unlock()
+
+	jr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (jr *JUnitReporter) Unwrap() []Reporter {
+	return []Reporter{jr.rep}
+}
+
+// testCase is a helper that constructs the test case for a unit.
+// The most severe error determines the outcome of the test case, and
+// all the errors are included in its text.
+func (jr *JUnitReporter) testCase(name string) junitTestCase {
+	tc := junitTestCase{
+		Name:      name,
+		ClassName: jr.suite,
+	}
+
+	// Find the most severe error and format the errors
+	var worst error
+	worstSev := SeverityDebug
+	lines := []string{}
+	for _, err := range jr.diags[name] {
+		if sev := SeverityOf(err); worst == nil || sev > worstSev {
+			worst = err
+			worstSev = sev
+		}
+		lines = append(lines, jr.format.Format(err))
+	}
+	if worst == nil {
+		return tc
+	}
+	text := strings.Join(lines, "\n")
+
+	// Determine the outcome
+	switch {
+	case worstSev >= SeverityError:
+		tc.Failure = &junitMessage{
+			Message: worst.Error(),
+			Type:    worstSev.String(),
+			Text:    text,
+		}
+
+	case worstSev == SeverityWarning:
+		tc.Skipped = &junitMessage{
+			Message: worst.Error(),
+			Text:    text,
+		}
+
+	default:
+		tc.SystemOut = text
+	}
+
+	return tc
+}
+
+// Close writes the JUnit XML document to the output stream.  The
+// document is only written once; subsequent calls to Close do
+// nothing.
+func (jr *JUnitReporter) Close() error {
+	// Lock the mutex for thread safety
+	jr.Lock()
+	defer jr.Unlock()
+
+	if jr.closed {
+		return nil
+	}
+	jr.closed = true
+
+	// Construct the test suite
+	suite := junitTestSuite{
+		Name:      jr.suite,
+		Tests:     len(jr.units),
+		TestCases: []junitTestCase{},
+	}
+	for _, name := range jr.units {
+		tc := jr.testCase(name)
+		if tc.Failure != nil {
+			suite.Failures++
+		} else if tc.Skipped != nil {
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	// Write the document
+	if _, err := io.WriteString(jr.out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(jr.out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&junitTestSuites{
+		Name:     jr.suite,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(jr.out, "\n")
+
+	return err
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (fw *failingWriter) Write(p []byte) (int, error) {
+	return 0, assert.AnError
+}
+
+func TestJUnitGroupDefaultFile(t *testing.T) {
+	err := WithScope(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"), "deploy")
+
+	result := JUnitGroupDefault(err)
+
+	assert.Equal(t, "file.cfg", result)
+}
+
+func TestJUnitGroupDefaultScope(t *testing.T) {
+	err := WithScope(WithPosition(errors.New("test error"), Position{Line: 3}), "deploy", "web") //nolint:goerr113
+
+	result := JUnitGroupDefault(err)
+
+	assert.Equal(t, "deploy > web", result)
+}
+
+func TestJUnitGroupDefaultNone(t *testing.T) {
+	result := JUnitGroupDefault(assert.AnError)
+
+	assert.Equal(t, "", result)
+}
+
+func TestJUnitReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &JUnitReporter{})
+}
+
+func TestJUnitSuite(t *testing.T) {
+	obj := &JUnitReporter{}
+
+	opt := JUnitSuite("linter")
+	opt(obj)
+
+	assert.Equal(t, &JUnitReporter{
+		suite: "linter",
+	}, obj)
+}
+
+func TestJUnitGroupBy(t *testing.T) {
+	obj := &JUnitReporter{}
+
+	opt := JUnitGroupBy(func(err error) string {
+		return "unit"
+	})
+	opt(obj)
+
+	assert.Equal(t, "unit", obj.group(assert.AnError))
+}
+
+func TestJUnitFormat(t *testing.T) {
+	obj := &JUnitReporter{}
+
+	opt := JUnitFormat(FormatError("E: %s"))
+	opt(obj)
+
+	assert.Equal(t, "E: test error", obj.format.Format(errors.New("test error"))) //nolint:goerr113
+}
+
+func TestNewJUnitReporterBase(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+
+	result := NewJUnitReporter(out, rep)
+
+	assert.Same(t, out, result.out)
+	assert.Equal(t, "kent", result.suite)
+	assert.NotNil(t, result.group)
+	assert.NotNil(t, result.format)
+	assert.Equal(t, map[string][]error{}, result.diags)
+	assert.Same(t, rep, result.rep)
+}
+
+func TestNewJUnitReporterOptions(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+	format := &Formatters{}
+	var opt1Called, opt2Called *JUnitReporter
+	options := []JUnitReporterOption{
+		func(jr *JUnitReporter) {
+			opt1Called = jr
+		},
+		func(jr *JUnitReporter) {
+			opt2Called = jr
+			jr.format = format
+		},
+	}
+
+	result := NewJUnitReporter(out, rep, options...)
+
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+	assert.Same(t, format, result.format)
+}
+
+func TestJUnitReporterUnit(t *testing.T) {
+	obj := &JUnitReporter{
+		units: []string{"a.cfg"},
+		diags: map[string][]error{
+			"a.cfg": {assert.AnError},
+		},
+	}
+
+	obj.Unit("b.cfg")
+	obj.Unit("a.cfg")
+
+	assert.Equal(t, []string{"a.cfg", "b.cfg"}, obj.units)
+	assert.Equal(t, map[string][]error{
+		"a.cfg": {assert.AnError},
+		"b.cfg": nil,
+	}, obj.diags)
+}
+
+func TestJUnitReporterReport(t *testing.T) {
+	err := ErrorAt(Position{File: "a.cfg", Line: 3}, "test error")
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	rep.On("Report", assert.AnError)
+	obj := &JUnitReporter{
+		group: JUnitGroupDefault,
+		diags: map[string][]error{},
+		rep:   rep,
+	}
+
+	obj.Report(err)
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, []string{"a.cfg", "(ungrouped)"}, obj.units)
+	assert.Equal(t, map[string][]error{
+		"a.cfg":       {err},
+		"(ungrouped)": {assert.AnError},
+	}, obj.diags)
+	rep.AssertExpectations(t)
+}
+
+func TestJUnitReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &JUnitReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestJUnitReporterTestCasePassing(t *testing.T) {
+	obj := &JUnitReporter{
+		suite: "linter",
+		diags: map[string][]error{},
+	}
+
+	result := obj.testCase("a.cfg")
+
+	assert.Equal(t, junitTestCase{
+		Name:      "a.cfg",
+		ClassName: "linter",
+	}, result)
+}
+
+func TestJUnitReporterTestCaseFailure(t *testing.T) {
+	obj := &JUnitReporter{
+		suite:  "linter",
+		format: NewFormatters(),
+		diags: map[string][]error{
+			"a.cfg": {
+				NewWarning("test warning"),
+				NewSeverity(SeverityFatal, "test fatal"),
+				errors.New("test error"), //nolint:goerr113
+			},
+		},
+	}
+
+	result := obj.testCase("a.cfg")
+
+	assert.Equal(t, junitTestCase{
+		Name:      "a.cfg",
+		ClassName: "linter",
+		Failure: &junitMessage{
+			Message: "test fatal",
+			Type:    "fatal",
+			Text:    "WARNING: test warning\nFATAL: test fatal\nERROR: test error",
+		},
+	}, result)
+}
+
+func TestJUnitReporterTestCaseSkipped(t *testing.T) {
+	obj := &JUnitReporter{
+		suite:  "linter",
+		format: NewFormatters(),
+		diags: map[string][]error{
+			"a.cfg": {
+				NewSeverity(SeverityInfo, "test info"),
+				NewWarning("test warning"),
+			},
+		},
+	}
+
+	result := obj.testCase("a.cfg")
+
+	assert.Equal(t, junitTestCase{
+		Name:      "a.cfg",
+		ClassName: "linter",
+		Skipped: &junitMessage{
+			Message: "test warning",
+			Text:    "INFO: test info\nWARNING: test warning",
+		},
+	}, result)
+}
+
+func TestJUnitReporterTestCaseSystemOut(t *testing.T) {
+	obj := &JUnitReporter{
+		suite:  "linter",
+		format: NewFormatters(),
+		diags: map[string][]error{
+			"a.cfg": {
+				NewSeverity(SeverityInfo, "test info"),
+			},
+		},
+	}
+
+	result := obj.testCase("a.cfg")
+
+	assert.Equal(t, junitTestCase{
+		Name:      "a.cfg",
+		ClassName: "linter",
+		SystemOut: "INFO: test info",
+	}, result)
+}
+
+func TestJUnitReporterClose(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewJUnitReporter(out, Root(), JUnitSuite("linter"))
+	obj.Unit("a.cfg")
+	obj.Unit("b.cfg")
+	obj.Report(ErrorAt(Position{File: "b.cfg", Line: 3}, "bad <value>"))
+	obj.Report(WarningAt(Position{File: "c.cfg", Line: 1}, "test warning"))
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="linter" tests="3" failures="1" errors="0" skipped="1">
+  <testsuite name="linter" tests="3" failures="1" errors="0" skipped="1">
+    <testcase name="a.cfg" classname="linter"></testcase>
+    <testcase name="b.cfg" classname="linter">
+      <failure message="bad &lt;value&gt;" type="error">b.cfg:3: ERROR: bad &lt;value&gt;</failure>
+    </testcase>
+    <testcase name="c.cfg" classname="linter">
+      <skipped message="test warning">c.cfg:1: WARNING: test warning</skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestJUnitReporterCloseTwice(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := &JUnitReporter{
+		out:    out,
+		closed: true,
+	}
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+}
+
+func TestJUnitReporterCloseWriteError(t *testing.T) {
+	obj := NewJUnitReporter(&failingWriter{}, Root())
+
+	err := obj.Close()
+
+	assert.Same(t, assert.AnError, err)
+}
@@ -29,10 +29,27 @@
 // and "WARNING:" prefixes; LoggingReporter, which is similar to
 // WritingReporter except that it writes to a log.Logger; TeeReporter,
 // which allows writing to parallel reporters, with dynamic addition
-// of additional reporters; and CapturingReporter, which allows
-// capturing the list of reported errors passed to the reporter.
-// Additionally, a MockReporter is provided to facilitate testing of
-// code that uses or manipulates Reporter instances.
+// of additional reporters; CapturingReporter, which allows capturing
+// the list of reported errors passed to the reporter; FatalReporter,
+// which records the first error with SeverityFatal and optionally
+// cancels a context, for use with the Check helper; ScopedReporter,
+// which tags reported errors with a hierarchical scope;
+// FilteringReporter, which passes on only the errors selected by a
+// Predicate; PromotingReporter, which promotes warnings to errors;
+// DedupReporter, which suppresses duplicate errors;
+// RateLimitingReporter, which limits the rate at which errors are
+// passed on; AsyncReporter, which passes on errors on a background
+// goroutine; and JSONReporter, which emits errors in a versioned JSON
+// Lines format that may be read back with a JSONDecoder.  Other
+// reporters accumulate the reported errors and write a report when
+// they are closed: SARIFReporter writes a SARIF 2.1.0 log;
+// JUnitReporter writes a JUnit XML document, with a test case for
+// each checked file or scope; CheckstyleReporter writes a Checkstyle
+// XML document; and SummaryReporter writes a summary such as "3
+// errors and 2 warnings generated".  The Close helper closes all the
+// reporters in a chain that implement io.Closer.  Additionally, a
+// MockReporter is provided to facilitate testing of code that uses or
+// manipulates Reporter instances.
 //
 // Both NewLoggingReporter and NewWritingReporter accept options of
 // type FormatOption.  These options can be used to specify how the
@@ -42,10 +59,26 @@
 // simple format string with FormatError and FormatWarning, or setting
 // a function which takes the error and returns a string with
 // FormatErrorFunc and FormatWarningFunc.
+//
+// Errors may also carry a Severity, ranging from SeverityDebug to
+// SeverityFatal.  The SeverityOf function determines the severity of
+// an error, treating a Warning as having SeverityWarning and any
+// error without severity information as having SeverityError.  The
+// FormatSeverity and FormatSeverityFunc options set the format for a
+// specific severity.
+//
+// Additional information may be attached to an error without altering
+// its message: a source Position with WithPosition, a diagnostic code
+// with WithCode, related notes with WithNotes, suggested fixes with
+// WithSuggestions, and structured fields with WithFields.  Helpers
+// such as PositionOf, CodeOf, Notes, Suggestions, and Fields retrieve
+// this information from anywhere in the tree of wrapped errors.
 package kent
 
 import (
 	"container/list"
+	"errors"
+	"io"
 	"reflect"
 )
 
@@ -116,3 +149,70 @@ func As(rep Reporter, target interface{}) bool {
 
 	return false
 }
+
+// joinType is the type of the errors constructed by errors.Join.
+var joinType = reflect.TypeOf(errors.Join(errors.New(""))) //nolint:goerr113
+
+// ReportAll is a helper that reports an error to a Reporter.  If the
+// error was constructed by errors.Join, each of the joined errors is
+// reported with a separate call to Report, so that reporters such as
+// CountingReporter and CapturingReporter treat each one separately;
+// nested joined errors are likewise reported individually.  Errors
+// wrapping multiple errors in other ways, such as those constructed
+// by fmt.Errorf with multiple %w verbs, are reported as a single
+// error.  Nothing is reported if the error is nil.
+func ReportAll(rep Reporter, err error) {
+	if err == nil {
+		return
+	}
+
+	if reflect.TypeOf(err) == joinType {
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			ReportAll(rep, e)
+		}
+
+		return
+	}
+
+	rep.Report(err)
+}
+
+// Close is a helper that closes all the Reporters in a chain of
+// wrapped Reporters that implement io.Closer, such as SARIFReporter.
+// Reporters are closed in breadth-first order starting with the
+// specified Reporter, so a Reporter is closed before the Reporters it
+// wraps; each Reporter is closed only once, even if it appears in the
+// chain more than once.  The errors returned by the Close methods are
+// joined using errors.Join.
+func Close(rep Reporter) error {
+	var errs []error
+	seen := map[Reporter]bool{}
+	q := list.List{}
+	q.PushBack(rep)
+	for q.Len() > 0 {
+		item := q.Front().Value.(Reporter)
+		q.Remove(q.Front())
+
+		// Skip reporters we've already seen
+		if reflect.TypeOf(item).Comparable() {
+			if seen[item] {
+				continue
+			}
+			seen[item] = true
+		}
+
+		// Close the reporter
+		if closer, ok := item.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		// Add the children to the work queue
+		for _, child := range item.Unwrap() {
+			q.PushBack(child)
+		}
+	}
+
+	return errors.Join(errs...)
+}
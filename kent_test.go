@@ -15,9 +15,12 @@
 package kent
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAsBase(t *testing.T) {
@@ -66,3 +69,92 @@ func TestAsBadTarget(t *testing.T) {
 	assert.PanicsWithValue(t, "*target must be interface or implement Reporter", func() { As(rep, &target) })
 	rep.AssertExpectations(t)
 }
+
+func TestReportAllNil(t *testing.T) {
+	rep := &MockReporter{}
+
+	ReportAll(rep, nil)
+
+	rep.AssertExpectations(t)
+}
+
+func TestReportAllSingle(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError)
+
+	ReportAll(rep, assert.AnError)
+
+	rep.AssertExpectations(t)
+}
+
+func TestReportAllJoined(t *testing.T) {
+	err1 := errors.New("error 1") //nolint:goerr113
+	err2 := NewWarning("warning 2")
+	err3 := errors.New("error 3") //nolint:goerr113
+	rep := NewCountingReporter(Root())
+	capt := NewCapturingReporter(rep)
+
+	ReportAll(capt, errors.Join(err1, errors.Join(err2, err3)))
+
+	assert.Equal(t, []error{err1, err2, err3}, capt.List())
+	assert.Equal(t, 2, rep.Errors())
+	assert.Equal(t, 1, rep.Warnings())
+}
+
+func TestReportAllMultiWrapped(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w and %w", assert.AnError, NewWarning("warning"))
+	rep := &MockReporter{}
+	rep.On("Report", err)
+
+	ReportAll(rep, err)
+
+	rep.AssertExpectations(t)
+}
+
+// closingReporter is a Reporter implementing io.Closer for testing
+// the Close helper.
+type closingReporter struct {
+	MockReporter
+}
+
+func (cr *closingReporter) Close() error {
+	args := cr.MethodCalled("Close")
+
+	return args.Error(0)
+}
+
+func TestCloseBase(t *testing.T) {
+	order := []string{}
+	rep1 := &closingReporter{}
+	rep1.On("Close").Return(nil).Run(func(mock.Arguments) { order = append(order, "rep1") })
+	rep2 := &closingReporter{}
+	rep2.On("Close").Return(nil).Run(func(mock.Arguments) { order = append(order, "rep2") })
+	rep2.On("Unwrap").Return([]Reporter{root})
+	rep3 := &closingReporter{}
+	rep3.On("Close").Return(nil).Run(func(mock.Arguments) { order = append(order, "rep3") })
+	rep3.On("Unwrap").Return([]Reporter{rep2})
+	rep4 := &MockReporter{}
+	rep4.On("Unwrap").Return([]Reporter{})
+	rep1.On("Unwrap").Return([]Reporter{rep3, rep4, rep2})
+
+	err := Close(NewCountingReporter(rep1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rep1", "rep3", "rep2"}, order)
+}
+
+func TestCloseErrors(t *testing.T) {
+	err1 := errors.New("error 1") //nolint:goerr113
+	err2 := errors.New("error 2") //nolint:goerr113
+	rep2 := &closingReporter{}
+	rep2.On("Close").Return(err2)
+	rep2.On("Unwrap").Return([]Reporter{root})
+	rep1 := &closingReporter{}
+	rep1.On("Close").Return(err1)
+	rep1.On("Unwrap").Return([]Reporter{rep2})
+
+	err := Close(rep1)
+
+	assert.True(t, errors.Is(err, err1))
+	assert.True(t, errors.Is(err, err2))
+}
@@ -35,11 +35,18 @@ type LoggingReporter struct {
 // To use different formats for errors and warnings, pass appropriate
 // formatting options, such as FormatError or FormatWarning.
 func NewLoggingReporter(logger *log.Logger, rep Reporter, formatOptions ...FormatOption) *LoggingReporter {
-	return &LoggingReporter{
+	obj := &LoggingReporter{
 		out:    logger,
 		rep:    rep,
 		format: newFormatters(formatOptions...),
 	}
+	if logger == nil {
+		obj.format.detectColor(log.Writer())
+	} else {
+		obj.format.detectColor(logger.Writer())
+	}
+
+	return obj
 }
 
 // emit is a helper that ensures that the output goes to the correct
@@ -17,6 +17,7 @@ package kent
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"testing"
 
@@ -62,6 +63,44 @@ func TestNewLoggingReporterOptions(t *testing.T) {
 	}, result)
 }
 
+func TestNewLoggingReporterColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	stream := &bytes.Buffer{}
+	out := log.New(stream, "", 0)
+	rep := &MockReporter{}
+	defer patcher.SetVar(&newFormatters, func(opts ...FormatOption) *Formatters {
+		return &Formatters{color: ColorAuto}
+	}).Install().Restore()
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		assert.Same(t, stream, w)
+		return true
+	}).Install().Restore()
+
+	result := NewLoggingReporter(out, rep)
+
+	assert.True(t, result.format.colorOut)
+}
+
+func TestNewLoggingReporterColorDefaultLogger(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	stream := &bytes.Buffer{}
+	defer patcher.Log(stream).Install().Restore()
+	rep := &MockReporter{}
+	defer patcher.SetVar(&newFormatters, func(opts ...FormatOption) *Formatters {
+		return &Formatters{color: ColorAuto}
+	}).Install().Restore()
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		assert.Same(t, stream, w)
+		return true
+	}).Install().Restore()
+
+	result := NewLoggingReporter(nil, rep)
+
+	assert.True(t, result.format.colorOut)
+}
+
 func TestLoggingReporterEmitDefaultLogger(t *testing.T) {
 	stream := &bytes.Buffer{}
 	defer patcher.Log(stream).Install().Restore()
@@ -119,6 +158,24 @@ func TestLoggingReporterReportWarning(t *testing.T) {
 	rep.AssertExpectations(t)
 }
 
+func TestLoggingReporterReportNotes(t *testing.T) {
+	err := WithNotes(NewWarning("test warning"), Notef("consider removing it"))
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	stream := &bytes.Buffer{}
+	out := log.New(stream, "", 0)
+	obj := &LoggingReporter{
+		out:    out,
+		rep:    rep,
+		format: NewFormatters(),
+	}
+
+	obj.Report(err)
+
+	assert.Equal(t, "WARNING: test warning\n    note: consider removing it\n", stream.String())
+	rep.AssertExpectations(t)
+}
+
 func TestLoggingReporterUnwrap(t *testing.T) {
 	rep := &MockReporter{}
 	obj := &LoggingReporter{
@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import "fmt"
+
+// Note describes a note related to a reported error, such as
+// "previously defined here".  A note may optionally refer to a
+// position other than that of the error.
+type Note struct {
+	Message  string   // The text of the note
+	Position Position // The position of the note; may be zero
+}
+
+// Notef constructs a Note without a position.  The message is
+// constructed as for fmt.Sprintf.
+func Notef(format string, args ...interface{}) Note {
+	return Note{
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// NoteAt constructs a Note with the specified position.  The message
+// is constructed as for fmt.Sprintf.
+func NoteAt(pos Position, format string, args ...interface{}) Note {
+	return Note{
+		Message:  fmt.Sprintf(format, args...),
+		Position: pos,
+	}
+}
+
+// String returns the string form of the note.  This will be of the
+// form "note: message", with the position prefixed if one is known.
+func (n Note) String() string {
+	if n.Position.known() {
+		return fmt.Sprintf("%s: note: %s", n.Position, n.Message)
+	}
+
+	return "note: " + n.Message
+}
+
+// Annotated is a utility interface for errors that carry related
+// notes.  The Notes helper collects the notes from all errors in an
+// error chain that implement Annotated.
+type Annotated interface {
+	error
+
+	// Notes returns the notes attached to the error.
+	Notes() []Note
+}
+
+// notedError is an implementation of Annotated that wraps another
+// error.
+type notedError struct {
+	notes []Note // The related notes
+	err   error  // The wrapped error
+}
+
+// Error returns the error message.
+func (ne *notedError) Error() string {
+	return ne.err.Error()
+}
+
+// Notes returns the notes attached to the error.
+func (ne *notedError) Notes() []Note {
+	return ne.notes
+}
+
+// Unwrap returns the wrapped error.
+func (ne *notedError) Unwrap() error {
+	return ne.err
+}
+
+// WithNotes attaches one or more related notes to an error.  The
+// error message is not altered.
+func WithNotes(err error, notes ...Note) error {
+	return &notedError{
+		notes: notes,
+		err:   err,
+	}
+}
+
+// Notes retrieves all the notes associated with an error.  Unlike
+// PositionOf or CodeOf, Notes follows the entire error chain and
+// collects the notes from every error implementing Annotated, with
+// the notes from outer errors preceding those from inner errors.
+func Notes(err error) []Note {
+	var notes []Note
+	walk(err, func(e error) bool {
+		if ae, ok := e.(Annotated); ok {
+			notes = append(notes, ae.Notes()...)
+		}
+
+		return false
+	})
+
+	return notes
+}
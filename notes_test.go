@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotef(t *testing.T) {
+	result := Notef("note %d", 42)
+
+	assert.Equal(t, Note{
+		Message: "note 42",
+	}, result)
+}
+
+func TestNoteAt(t *testing.T) {
+	pos := Position{File: "file.cfg", Line: 3}
+
+	result := NoteAt(pos, "note %d", 42)
+
+	assert.Equal(t, Note{
+		Message:  "note 42",
+		Position: pos,
+	}, result)
+}
+
+func TestNoteStringPosition(t *testing.T) {
+	obj := Note{
+		Message:  "previously defined here",
+		Position: Position{File: "file.cfg", Line: 3, Column: 5},
+	}
+
+	result := obj.String()
+
+	assert.Equal(t, "file.cfg:3:5: note: previously defined here", result)
+}
+
+func TestNoteStringNoPosition(t *testing.T) {
+	obj := Note{
+		Message: "previously defined here",
+	}
+
+	result := obj.String()
+
+	assert.Equal(t, "note: previously defined here", result)
+}
+
+func TestNotedErrorImplementsAnnotated(t *testing.T) {
+	assert.Implements(t, (*Annotated)(nil), &notedError{})
+}
+
+func TestNotedErrorError(t *testing.T) {
+	obj := &notedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestNotedErrorNotes(t *testing.T) {
+	obj := &notedError{
+		notes: []Note{{Message: "note"}},
+	}
+
+	result := obj.Notes()
+
+	assert.Equal(t, []Note{{Message: "note"}}, result)
+}
+
+func TestNotedErrorUnwrap(t *testing.T) {
+	obj := &notedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestWithNotes(t *testing.T) {
+	result := WithNotes(assert.AnError, Note{Message: "note 1"}, Note{Message: "note 2"})
+
+	assert.Equal(t, &notedError{
+		notes: []Note{{Message: "note 1"}, {Message: "note 2"}},
+		err:   assert.AnError,
+	}, result)
+}
+
+func TestNotesChain(t *testing.T) {
+	err := WithNotes(
+		fmt.Errorf("wrapped: %w", WithNotes(assert.AnError, Note{Message: "inner"})),
+		Note{Message: "outer"},
+	)
+
+	result := Notes(err)
+
+	assert.Equal(t, []Note{{Message: "outer"}, {Message: "inner"}}, result)
+}
+
+func TestNotesMissing(t *testing.T) {
+	result := Notes(assert.AnError)
+
+	assert.Nil(t, result)
+}
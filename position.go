@@ -0,0 +1,134 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Position describes a location within a source file, such as a
+// configuration file.  A Position is valid if its line number is
+// greater than 0.
+type Position struct {
+	File   string    // The name of the file, if any
+	Line   int       // Line number, starting at 1
+	Column int       // Column number (byte count), starting at 1
+	Offset int       // Byte offset, starting at 0
+	End    *Position // Optional end of the range
+}
+
+// IsValid returns true if the position is valid.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// known is a helper that returns true if anything is known about the
+// position, including just the file name.
+func (p Position) known() bool {
+	return p.File != "" || p.IsValid()
+}
+
+// String returns a string form of the position.  The string will
+// have one of the forms "file:line:column", "file:line", "file",
+// "line:column", or "line"; if nothing is known about the position,
+// "-" is returned.
+func (p Position) String() string {
+	s := p.File
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			s += ":" + strconv.Itoa(p.Column)
+		}
+	}
+
+	if s == "" {
+		s = "-"
+	}
+
+	return s
+}
+
+// Positioned is a utility interface for errors that are associated
+// with a Position.  Errors implementing Positioned are discoverable
+// using errors.As, or by using the PositionOf helper.
+type Positioned interface {
+	error
+
+	// Position returns the position associated with the error.
+	Position() Position
+}
+
+// positionError is an implementation of Positioned that wraps
+// another error.
+type positionError struct {
+	pos Position // The position
+	err error    // The wrapped error
+}
+
+// Error returns the error message.
+func (pe *positionError) Error() string {
+	return pe.err.Error()
+}
+
+// Position returns the position associated with the error.
+func (pe *positionError) Position() Position {
+	return pe.pos
+}
+
+// Unwrap returns the wrapped error.
+func (pe *positionError) Unwrap() error {
+	return pe.err
+}
+
+// WithPosition attaches a position to an error.  The error message
+// is not altered.
+func WithPosition(err error, pos Position) error {
+	return &positionError{
+		pos: pos,
+		err: err,
+	}
+}
+
+// ErrorAt constructs a new error associated with the specified
+// position.  It is the equivalent of fmt.Errorf for positioned
+// errors.
+func ErrorAt(pos Position, format string, args ...interface{}) error {
+	return WithPosition(fmt.Errorf(format, args...), pos) //nolint:goerr113
+}
+
+// WarningAt constructs a new warning associated with the specified
+// position.  It is the equivalent of Warningf for positioned
+// warnings.
+func WarningAt(pos Position, format string, args ...interface{}) error {
+	return WithPosition(Warningf(format, args...), pos)
+}
+
+// PositionOf retrieves the position associated with an error.  It
+// returns the position of the first error in the chain that
+// implements Positioned, along with a boolean true; if no position
+// is available, it returns a zero Position and false.
+func PositionOf(err error) (Position, bool) {
+	var pe Positioned
+	if errors.As(err, &pe) {
+		return pe.Position(), true
+	}
+
+	return Position{}, false
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionIsValid(t *testing.T) {
+	assert.True(t, Position{Line: 1}.IsValid())
+	assert.False(t, Position{File: "file.cfg"}.IsValid())
+}
+
+func TestPositionKnown(t *testing.T) {
+	assert.True(t, Position{Line: 1}.known())
+	assert.True(t, Position{File: "file.cfg"}.known())
+	assert.False(t, Position{Column: 3}.known())
+}
+
+func TestPositionString(t *testing.T) {
+	assert.Equal(t, "file.cfg:3:5", Position{File: "file.cfg", Line: 3, Column: 5}.String())
+	assert.Equal(t, "file.cfg:3", Position{File: "file.cfg", Line: 3}.String())
+	assert.Equal(t, "file.cfg", Position{File: "file.cfg"}.String())
+	assert.Equal(t, "3:5", Position{Line: 3, Column: 5}.String())
+	assert.Equal(t, "3", Position{Line: 3}.String())
+	assert.Equal(t, "-", Position{}.String())
+}
+
+func TestPositionErrorImplementsPositioned(t *testing.T) {
+	assert.Implements(t, (*Positioned)(nil), &positionError{})
+}
+
+func TestPositionErrorError(t *testing.T) {
+	obj := &positionError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestPositionErrorPosition(t *testing.T) {
+	obj := &positionError{
+		pos: Position{File: "file.cfg", Line: 3},
+	}
+
+	result := obj.Position()
+
+	assert.Equal(t, Position{File: "file.cfg", Line: 3}, result)
+}
+
+func TestPositionErrorUnwrap(t *testing.T) {
+	obj := &positionError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestWithPosition(t *testing.T) {
+	pos := Position{File: "file.cfg", Line: 3}
+
+	result := WithPosition(assert.AnError, pos)
+
+	assert.Equal(t, &positionError{
+		pos: pos,
+		err: assert.AnError,
+	}, result)
+}
+
+func TestErrorAt(t *testing.T) {
+	pos := Position{File: "file.cfg", Line: 3}
+
+	result := ErrorAt(pos, "this is a test %w", assert.AnError)
+
+	assert.Equal(t, fmt.Sprintf("this is a test %s", assert.AnError), result.Error())
+	assert.False(t, IsWarning(result))
+	assert.True(t, errors.Is(result, assert.AnError))
+	resultPos, ok := PositionOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, pos, resultPos)
+}
+
+func TestWarningAt(t *testing.T) {
+	pos := Position{File: "file.cfg", Line: 3}
+
+	result := WarningAt(pos, "this is a test %w", assert.AnError)
+
+	assert.Equal(t, fmt.Sprintf("this is a test %s", assert.AnError), result.Error())
+	assert.True(t, IsWarning(result))
+	assert.True(t, errors.Is(result, assert.AnError))
+	resultPos, ok := PositionOf(result)
+	assert.True(t, ok)
+	assert.Equal(t, pos, resultPos)
+}
+
+func TestPositionOfWrapped(t *testing.T) {
+	pos := Position{File: "file.cfg", Line: 3}
+	err := fmt.Errorf("wrapped: %w", WithPosition(assert.AnError, pos))
+
+	result, ok := PositionOf(err)
+
+	assert.True(t, ok)
+	assert.Equal(t, pos, result)
+}
+
+func TestPositionOfMissing(t *testing.T) {
+	result, ok := PositionOf(assert.AnError)
+
+	assert.False(t, ok)
+	assert.Equal(t, Position{}, result)
+}
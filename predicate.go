@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+)
+
+// errorType is the type of error.
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Predicate describes a function that selects errors, such as for
+// use with a FilteringReporter.  It will be passed the error and must
+// return true if the error is selected.  Predicates may be combined
+// using And, Or, and Not.
+type Predicate func(err error) bool
+
+// IsWarningPred is a Predicate that selects warnings, as determined
+// by IsWarning.
+func IsWarningPred(err error) bool {
+	return IsWarning(err)
+}
+
+// MatchesIs constructs a Predicate that selects errors matching the
+// specified target, as determined by errors.Is.
+func MatchesIs(target error) Predicate {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// MatchesAs constructs a Predicate that selects errors having an
+// error in their chain that is assignable to the type pointed to by
+// target, as determined by errors.As.  The target is only used to
+// determine the type, and is never assigned to, so the Predicate may
+// safely be used concurrently; a typical call is:
+//
+//	kent.MatchesAs(new(*fs.PathError))
+//
+// As with errors.As, MatchesAs panics if target is not a pointer to
+// either a type that implements error or to any interface type.
+func MatchesAs(target interface{}) Predicate {
+	if target == nil {
+		panic("target cannot be nil")
+	}
+	typ := reflect.TypeOf(target)
+	if typ.Kind() != reflect.Ptr {
+		panic("target must be a pointer")
+	}
+	elem := typ.Elem()
+	if elem.Kind() != reflect.Interface && !elem.Implements(errorType) {
+		panic("*target must be interface or implement error")
+	}
+
+	return func(err error) bool {
+		return errors.As(err, reflect.New(elem).Interface())
+	}
+}
+
+// MessageRegexp constructs a Predicate that selects errors with
+// messages matching the specified regular expression.
+func MessageRegexp(re *regexp.Regexp) Predicate {
+	return func(err error) bool {
+		return re.MatchString(err.Error())
+	}
+}
+
+// And constructs a Predicate that selects errors selected by all of
+// the specified predicates.  If no predicates are specified, all
+// errors are selected.
+func And(preds ...Predicate) Predicate {
+	return func(err error) bool {
+		for _, pred := range preds {
+			if !pred(err) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Or constructs a Predicate that selects errors selected by any of
+// the specified predicates.  If no predicates are specified, no
+// errors are selected.
+func Or(preds ...Predicate) Predicate {
+	return func(err error) bool {
+		for _, pred := range preds {
+			if pred(err) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not constructs a Predicate that selects errors not selected by the
+// specified predicate.
+func Not(pred Predicate) Predicate {
+	return func(err error) bool {
+		return !pred(err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWarningPredWarning(t *testing.T) {
+	assert.True(t, IsWarningPred(NewWarning("a warning")))
+}
+
+func TestIsWarningPredError(t *testing.T) {
+	assert.False(t, IsWarningPred(assert.AnError))
+}
+
+func TestMatchesIs(t *testing.T) {
+	pred := MatchesIs(fs.ErrNotExist)
+
+	assert.True(t, pred(fmt.Errorf("wrapped: %w", fs.ErrNotExist)))
+	assert.False(t, pred(assert.AnError))
+}
+
+func TestMatchesAs(t *testing.T) {
+	target := new(*fs.PathError)
+	pred := MatchesAs(target)
+
+	assert.True(t, pred(fmt.Errorf("wrapped: %w", &fs.PathError{Op: "open", Path: "file", Err: fs.ErrNotExist})))
+	assert.False(t, pred(assert.AnError))
+	assert.Nil(t, *target)
+}
+
+func TestMatchesAsInterface(t *testing.T) {
+	pred := MatchesAs(new(Warning))
+
+	assert.True(t, pred(NewWarning("a warning")))
+	assert.False(t, pred(assert.AnError))
+}
+
+func TestMatchesAsNilPointer(t *testing.T) {
+	pred := MatchesAs((**fs.PathError)(nil))
+
+	assert.True(t, pred(&fs.PathError{Op: "open", Path: "file", Err: fs.ErrNotExist}))
+}
+
+func TestMatchesAsNil(t *testing.T) {
+	assert.PanicsWithValue(t, "target cannot be nil", func() { MatchesAs(nil) })
+}
+
+func TestMatchesAsNonPointer(t *testing.T) {
+	assert.PanicsWithValue(t, "target must be a pointer", func() { MatchesAs(fs.PathError{}) })
+}
+
+func TestMatchesAsBadTarget(t *testing.T) {
+	assert.PanicsWithValue(t, "*target must be interface or implement error", func() { MatchesAs(&fs.PathError{}) })
+}
+
+func TestMessageRegexp(t *testing.T) {
+	pred := MessageRegexp(regexp.MustCompile(`^deprecated`))
+
+	assert.True(t, pred(errors.New("deprecated option")))
+	assert.False(t, pred(errors.New("option is deprecated")))
+}
+
+func TestAnd(t *testing.T) {
+	pred := And(IsWarningPred, MessageRegexp(regexp.MustCompile(`option`)))
+
+	assert.True(t, pred(NewWarning("deprecated option")))
+	assert.False(t, pred(NewWarning("deprecated field")))
+	assert.False(t, pred(errors.New("bad option")))
+}
+
+func TestAndEmpty(t *testing.T) {
+	pred := And()
+
+	assert.True(t, pred(assert.AnError))
+}
+
+func TestOr(t *testing.T) {
+	pred := Or(IsWarningPred, MatchesIs(fs.ErrNotExist))
+
+	assert.True(t, pred(NewWarning("a warning")))
+	assert.True(t, pred(fs.ErrNotExist))
+	assert.False(t, pred(assert.AnError))
+}
+
+func TestOrEmpty(t *testing.T) {
+	pred := Or()
+
+	assert.False(t, pred(assert.AnError))
+}
+
+func TestNot(t *testing.T) {
+	pred := Not(IsWarningPred)
+
+	assert.True(t, pred(assert.AnError))
+	assert.False(t, pred(NewWarning("a warning")))
+}
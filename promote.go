@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+// PromotingReporter is a Reporter that promotes warnings to errors
+// before passing them on, similar to the -Werror option of many
+// compilers.  The promotion may be limited to warnings with selected
+// diagnostic codes or matching selected predicates.
+type PromotingReporter struct {
+	codes map[string]bool // Codes of the warnings to promote
+	preds []Predicate     // Predicates selecting warnings to promote
+	rep   Reporter        // Child reporter
+}
+
+// PromotingReporterOption describes an option for a
+// PromotingReporter.
+type PromotingReporterOption func(*PromotingReporter)
+
+// PromoteCodes specifies that warnings with the specified diagnostic
+// codes (see CodeOf) should be promoted.  It may be passed more than
+// once.  Passing the option with no codes, such as from an empty
+// configuration list, promotes no warnings by code; it does not
+// restore the default of promoting all warnings.
+func PromoteCodes(codes ...string) PromotingReporterOption {
+	return func(pr *PromotingReporter) {
+		if pr.codes == nil {
+			pr.codes = map[string]bool{}
+		}
+		for _, code := range codes {
+			pr.codes[code] = true
+		}
+	}
+}
+
+// PromoteIf specifies that warnings selected by the specified
+// Predicate should be promoted.  It may be passed more than once.
+func PromoteIf(pred Predicate) PromotingReporterOption {
+	return func(pr *PromotingReporter) {
+		pr.preds = append(pr.preds, pred)
+	}
+}
+
+// NewPromotingReporter constructs a new promoting reporter.  A
+// promoting reporter promotes the reported warnings, as determined by
+// IsWarning, to errors with SeverityError before passing them on, so
+// that, for instance, a CountingReporter counts them as errors.  If
+// the PromoteCodes or PromoteIf options are passed, only warnings with
+// one of the specified codes or selected by one of the specified
+// predicates are promoted; otherwise, all warnings are promoted.  The
+// promoted error has the same message as the warning, which remains
+// available using errors.Unwrap.
+func NewPromotingReporter(rep Reporter, options ...PromotingReporterOption) *PromotingReporter {
+	obj := &PromotingReporter{
+		rep: rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// selected is a helper that determines whether a warning should be
+// promoted.
+func (pr *PromotingReporter) selected(err error) bool {
+	if pr.codes == nil && pr.preds == nil {
+		return true
+	}
+
+	if code, ok := CodeOf(err); ok && pr.codes[code] {
+		return true
+	}
+	for _, pred := range pr.preds {
+		if pred(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (pr *PromotingReporter) Report(err error) {
+	if IsWarning(err) && pr.selected(err) {
+		err = &severityError{
+			sev: SeverityError,
+			msg: err.Error(),
+			err: err,
+		}
+	}
+
+	pr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (pr *PromotingReporter) Unwrap() []Reporter {
+	return []Reporter{pr.rep}
+}
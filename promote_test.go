@@ -0,0 +1,226 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotingReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &PromotingReporter{})
+}
+
+func TestPromoteCodes(t *testing.T) {
+	obj := &PromotingReporter{}
+
+	opt1 := PromoteCodes("CFG1001", "CFG1002")
+	opt1(obj)
+	opt2 := PromoteCodes("CFG1003")
+	opt2(obj)
+
+	assert.Equal(t, map[string]bool{
+		"CFG1001": true,
+		"CFG1002": true,
+		"CFG1003": true,
+	}, obj.codes)
+}
+
+func TestPromoteCodesEmpty(t *testing.T) {
+	obj := &PromotingReporter{}
+
+	opt := PromoteCodes()
+	opt(obj)
+
+	assert.Equal(t, map[string]bool{}, obj.codes)
+}
+
+func TestPromotingReporterReportCodesEmpty(t *testing.T) {
+	counter := NewCountingReporter(Root())
+	obj := NewPromotingReporter(counter, PromoteCodes())
+
+	obj.Report(Warningf("a warning"))
+
+	assert.Equal(t, 0, counter.Errors())
+	assert.Equal(t, 1, counter.Warnings())
+}
+
+func TestPromoteIf(t *testing.T) {
+	obj := &PromotingReporter{}
+
+	opt1 := PromoteIf(IsWarningPred)
+	opt1(obj)
+	opt2 := PromoteIf(Not(IsWarningPred))
+	opt2(obj)
+
+	assert.Len(t, obj.preds, 2)
+	assert.True(t, obj.preds[0](NewWarning("a warning")))
+	assert.True(t, obj.preds[1](assert.AnError))
+}
+
+func TestNewPromotingReporterBase(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewPromotingReporter(rep)
+
+	assert.Equal(t, &PromotingReporter{
+		rep: rep,
+	}, result)
+}
+
+func TestNewPromotingReporterOptions(t *testing.T) {
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *PromotingReporter
+	options := []PromotingReporterOption{
+		func(pr *PromotingReporter) {
+			opt1Called = pr
+		},
+		func(pr *PromotingReporter) {
+			opt2Called = pr
+		},
+	}
+
+	result := NewPromotingReporter(rep, options...)
+
+	assert.Equal(t, &PromotingReporter{
+		rep: rep,
+	}, result)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestPromotingReporterSelectedAll(t *testing.T) {
+	obj := &PromotingReporter{}
+
+	result := obj.selected(NewWarning("a warning"))
+
+	assert.True(t, result)
+}
+
+func TestPromotingReporterSelectedCode(t *testing.T) {
+	obj := &PromotingReporter{
+		codes: map[string]bool{"CFG1001": true},
+	}
+
+	assert.True(t, obj.selected(WithCode(NewWarning("a warning"), "CFG1001")))
+	assert.False(t, obj.selected(WithCode(NewWarning("a warning"), "CFG1002")))
+	assert.False(t, obj.selected(NewWarning("a warning")))
+}
+
+func TestPromotingReporterSelectedPredicate(t *testing.T) {
+	obj := &PromotingReporter{
+		preds: []Predicate{
+			MessageRegexp(regexp.MustCompile(`deprecated`)),
+			MessageRegexp(regexp.MustCompile(`unused`)),
+		},
+	}
+
+	assert.True(t, obj.selected(NewWarning("deprecated option")))
+	assert.True(t, obj.selected(NewWarning("unused option")))
+	assert.False(t, obj.selected(NewWarning("other option")))
+}
+
+func TestPromotingReporterReportWarning(t *testing.T) {
+	warning := NewWarning("a warning")
+	promoted := &severityError{
+		sev: SeverityError,
+		msg: "a warning",
+		err: warning,
+	}
+	rep := &MockReporter{}
+	rep.On("Report", promoted)
+	obj := &PromotingReporter{
+		rep: rep,
+	}
+
+	obj.Report(warning)
+
+	rep.AssertExpectations(t)
+}
+
+func TestPromotingReporterReportNotSelected(t *testing.T) {
+	warning := NewWarning("a warning")
+	rep := &MockReporter{}
+	rep.On("Report", warning)
+	obj := &PromotingReporter{
+		codes: map[string]bool{"CFG1001": true},
+		rep:   rep,
+	}
+
+	obj.Report(warning)
+
+	rep.AssertExpectations(t)
+}
+
+func TestPromotingReporterReportError(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError)
+	obj := &PromotingReporter{
+		rep: rep,
+	}
+
+	obj.Report(assert.AnError)
+
+	rep.AssertExpectations(t)
+}
+
+func TestPromotingReporterReportNotice(t *testing.T) {
+	notice := NewSeverity(SeverityNotice, "a notice")
+	rep := &MockReporter{}
+	rep.On("Report", notice)
+	obj := &PromotingReporter{
+		rep: rep,
+	}
+
+	obj.Report(notice)
+
+	rep.AssertExpectations(t)
+}
+
+func TestPromotingReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &PromotingReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestPromotingReporterCounting(t *testing.T) {
+	capture := NewCapturingReporter(Root())
+	counter := NewCountingReporter(capture)
+	obj := NewPromotingReporter(counter, PromoteCodes("CFG1001"))
+	warning := WithCode(WarningAt(Position{File: "file", Line: 3}, "a warning"), "CFG1001")
+
+	obj.Report(warning)
+	obj.Report(WithCode(NewWarning("another warning"), "CFG1002"))
+
+	assert.Equal(t, 1, counter.Errors())
+	assert.Equal(t, 1, counter.Warnings())
+	promoted := capture.List()[0]
+	assert.Equal(t, SeverityError, SeverityOf(promoted))
+	assert.Equal(t, "a warning", promoted.Error())
+	assert.Same(t, warning, errors.Unwrap(promoted))
+	assert.True(t, IsWarning(errors.Unwrap(promoted)))
+	code, _ := CodeOf(promoted)
+	assert.Equal(t, "CFG1001", code)
+	pos, _ := PositionOf(promoted)
+	assert.Equal(t, "file:3", pos.String())
+}
@@ -0,0 +1,372 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket used by RateLimitingReporter.
+type tokenBucket struct {
+	tokens float64   // Number of tokens available
+	last   time.Time // When the tokens were last refilled
+}
+
+// newTokenBucket constructs a full token bucket.
+func newTokenBucket(now time.Time, burst int) *tokenBucket {
+	return &tokenBucket{
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the bucket was last
+// refilled, at the specified rate in tokens per second, without
+// exceeding the burst size.  It returns true if the bucket is full.
+func (tb *tokenBucket) refill(now time.Time, limit float64, burst int) bool {
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens += elapsed.Seconds() * limit
+		tb.last = now
+	}
+	if tb.tokens >= float64(burst) {
+		tb.tokens = float64(burst)
+		return true
+	}
+
+	return false
+}
+
+// rateLimit describes a token bucket rate limit.
+type rateLimit struct {
+	limit float64 // Rate in diagnostics per second
+	burst int     // Maximum burst size
+}
+
+// describeInterval is a helper that describes an interval for the
+// synthetic warning reported by RateLimitingReporter, e.g., "minute"
+// or "30s".
+func describeInterval(d time.Duration) string {
+	switch d {
+	case time.Second:
+		return "second"
+	case time.Minute:
+		return "minute"
+	case time.Hour:
+		return "hour"
+	}
+
+	return d.String()
+}
+
+// TickerFunc describes a function that starts a ticker delivering
+// ticks at the specified interval.  It must return the channel on
+// which the ticks are delivered and a function that stops the ticker.
+type TickerFunc func(d time.Duration) (<-chan time.Time, func())
+
+// TimeTicker is a TickerFunc that starts a ticker using
+// time.NewTicker.
+func TimeTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+
+	return ticker.C, ticker.Stop
+}
+
+// RateLimitingReporter is a Reporter that limits the rate at which
+// errors are passed on, using a token bucket.  Excess errors are
+// dropped and counted, and a warning summarizing the number of
+// dropped errors is reported periodically.
+type RateLimitingReporter struct {
+	sync.Mutex
+
+	global     rateLimit               // Global rate limit
+	perKey     rateLimit               // Rate limit for each key
+	key        func(error) string      // Function for the key
+	interval   time.Duration           // Interval for summaries
+	now        func() time.Time        // Clock for the token buckets
+	ticker     TickerFunc              // Ticker for periodic summaries
+	stop       func()                  // Stops the periodic summaries
+	bucket     *tokenBucket            // Global token bucket
+	buckets    map[string]*tokenBucket // Token buckets by key
+	start      time.Time               // Start of the current interval
+	dropped    int                     // Number dropped in the interval
+	suppressed int                     // Number dropped in total
+	rep        Reporter                // Child reporter
+}
+
+// RateLimitingReporterOption describes an option for a
+// RateLimitingReporter.
+type RateLimitingReporterOption func(*RateLimitingReporter)
+
+// RateLimitPerKey specifies an additional rate limit that applies
+// separately to the errors with each key, as determined by the
+// specified function.  The limit is expressed in errors per second,
+// and burst is the number of errors that may be passed on at once; a
+// burst less than 1 is treated as 1, since otherwise no errors could
+// ever be passed on.  Errors for which the function returns an empty
+// string are subject only to the global rate limit.
+func RateLimitPerKey(key func(err error) string, limit float64, burst int) RateLimitingReporterOption {
+	return func(rr *RateLimitingReporter) {
+		rr.key = key
+		rr.perKey = rateLimit{limit: limit, burst: burst}
+	}
+}
+
+// RateLimitInterval specifies the interval at which the warning
+// summarizing the number of dropped errors is reported.  The default
+// is one minute.
+func RateLimitInterval(interval time.Duration) RateLimitingReporterOption {
+	return func(rr *RateLimitingReporter) {
+		rr.interval = interval
+	}
+}
+
+// RateLimitClock specifies a function that returns the current time,
+// for use in place of time.Now.  This is intended for testing.
+func RateLimitClock(now func() time.Time) RateLimitingReporterOption {
+	return func(rr *RateLimitingReporter) {
+		rr.now = now
+	}
+}
+
+// RateLimitTicker specifies that the warning summarizing the number
+// of dropped errors should be reported periodically, at the interval
+// specified by the RateLimitInterval option, by a goroutine driven by
+// a ticker started with the specified function, such as TimeTicker.
+// Without this option, the warning is only reported by the first call
+// to Report after the interval has elapsed, so dropped errors are not
+// reported until another error is reported.  The goroutine is stopped
+// when the reporter is closed.
+func RateLimitTicker(ticker TickerFunc) RateLimitingReporterOption {
+	return func(rr *RateLimitingReporter) {
+		rr.ticker = ticker
+	}
+}
+
+// NewRateLimitingReporter constructs a new rate-limiting reporter.  A
+// rate-limiting reporter passes on the reported errors at no more
+// than the specified rate, in errors per second, with bursts of up to
+// burst errors, where a burst less than 1 is treated as 1, since
+// otherwise no errors could ever be passed on; if limit is not
+// positive, there is no global limit, and only the limit specified by
+// the RateLimitPerKey option, if any, applies.  Excess errors are
+// dropped.  Once the interval specified by the RateLimitInterval
+// option has elapsed, the next call to Report also reports a warning
+// such as "suppressed 1234 diagnostics in the last minute" if any
+// errors were dropped; the warning is not subject to the rate limit.
+// If the RateLimitTicker option is passed, the warning is instead
+// reported periodically, regardless of whether further errors are
+// reported.  Any pending warning is also reported when the reporter
+// is closed, such as by the Close helper.
+func NewRateLimitingReporter(rep Reporter, limit float64, burst int, options ...RateLimitingReporterOption) *RateLimitingReporter {
+	obj := &RateLimitingReporter{
+		global:   rateLimit{limit: limit, burst: burst},
+		interval: time.Minute,
+		now:      timeNow,
+		buckets:  map[string]*tokenBucket{},
+		rep:      rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	// Make sure at least one error can be passed on
+	if obj.global.burst < 1 {
+		obj.global.burst = 1
+	}
+	if obj.key != nil && obj.perKey.burst < 1 {
+		obj.perKey.burst = 1
+	}
+
+	obj.start = obj.now()
+	obj.bucket = newTokenBucket(obj.start, obj.global.burst)
+
+	// Start the periodic summaries
+	if obj.ticker != nil {
+		ticks, stopTicker := obj.ticker(obj.interval)
+		done := make(chan struct{})
+		exited := make(chan struct{})
+		go obj.flush(ticks, done, exited)
+		obj.stop = func() {
+			stopTicker()
+			close(done)
+			<-exited
+		}
+	}
+
+	return obj
+}
+
+// flush reports the warning summarizing the number of dropped errors
+// each time the ticker ticks, until done is closed.  It closes exited
+// when it returns.
+func (rr *RateLimitingReporter) flush(ticks <-chan time.Time, done, exited chan struct{}) {
+	defer close(exited)
+
+	for {
+		select {
+		case <-ticks:
+			rr.Lock()
+			summary := rr.summarize(rr.now(), true)
+			rr.Unlock()
+
+			if summary != nil {
+				rr.rep.Report(summary)
+			}
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (rr *RateLimitingReporter) Report(err error) {
+	key := ""
+	if rr.key != nil {
+		key = rr.key(err)
+	}
+
+	rr.Lock()
+	now := rr.now()
+	summary := rr.summarize(now, false)
+	allow := rr.allow(now, key)
+	if !allow {
+		rr.dropped++
+		rr.suppressed++
+	}
+	rr.Unlock()
+
+	if summary != nil {
+		rr.rep.Report(summary)
+	}
+	if allow {
+		rr.rep.Report(err)
+	}
+}
+
+// allow is a helper that determines whether an error with the
+// specified key may be passed on, consuming a token from each
+// applicable token bucket if so.  It must be called with the mutex
+// held.
+func (rr *RateLimitingReporter) allow(now time.Time, key string) bool {
+	buckets := []*tokenBucket{}
+	if rr.global.limit > 0 {
+		rr.bucket.refill(now, rr.global.limit, rr.global.burst)
+		buckets = append(buckets, rr.bucket)
+	}
+	if key != "" {
+		bucket, ok := rr.buckets[key]
+		if !ok {
+			bucket = newTokenBucket(now, rr.perKey.burst)
+			rr.buckets[key] = bucket
+		}
+		bucket.refill(now, rr.perKey.limit, rr.perKey.burst)
+		buckets = append(buckets, bucket)
+	}
+
+	// Make sure there is a token in every bucket
+	for _, bucket := range buckets {
+		if bucket.tokens < 1 {
+			return false
+		}
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+
+	return true
+}
+
+// summarize is a helper that constructs the warning summarizing the
+// number of dropped errors, if the interval has elapsed or force is
+// true, and begins a new interval.  It returns nil if no warning
+// should be reported.  It must be called with the mutex held.
+func (rr *RateLimitingReporter) summarize(now time.Time, force bool) error {
+	elapsed := now.Sub(rr.start)
+	if elapsed < rr.interval && !force {
+		return nil
+	}
+
+	// Discard the token buckets that have been refilled
+	for key, bucket := range rr.buckets {
+		if bucket.refill(now, rr.perKey.limit, rr.perKey.burst) {
+			delete(rr.buckets, key)
+		}
+	}
+
+	// Begin a new interval
+	dropped := rr.dropped
+	rr.start = now
+	rr.dropped = 0
+	if dropped == 0 {
+		return nil
+	}
+
+	// Errors are only dropped by a call to Report, so no errors were
+	// dropped after the end of the interval
+	period := rr.interval
+	if elapsed < period {
+		period = elapsed.Round(time.Millisecond)
+	}
+
+	return Warningf("suppressed %s in the last %s", plural(dropped, "diagnostic", "diagnostics"), describeInterval(period))
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (rr *RateLimitingReporter) Unwrap() []Reporter {
+	return []Reporter{rr.rep}
+}
+
+// Suppressed returns the total number of errors dropped so far by the
+// rate-limiting reporter.
+func (rr *RateLimitingReporter) Suppressed() int {
+	// Lock the mutex for thread safety
+	rr.Lock()
+	defer rr.Unlock()
+
+	return rr.suppressed
+}
+
+// Close reports the warning summarizing the number of errors dropped
+// in the current interval, if any.  If the RateLimitTicker option was
+// passed, the periodic summaries are stopped.
+func (rr *RateLimitingReporter) Close() error {
+	// Stop the periodic summaries
+	rr.Lock()
+	stop := rr.stop
+	rr.stop = nil
+	rr.Unlock()
+	if stop != nil {
+		stop()
+	}
+
+	// Lock the mutex for thread safety
+	rr.Lock()
+	summary := rr.summarize(rr.now(), true)
+	rr.Unlock()
+
+	if summary != nil {
+		rr.rep.Report(summary)
+	}
+
+	return nil
+}
@@ -0,0 +1,531 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeClock is a clock for testing RateLimitingReporter.
+type fakeClock struct {
+	now time.Time
+}
+
+// Now returns the current time of the clock.
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+// Advance advances the clock.
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.now = fc.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewTokenBucket(t *testing.T) {
+	now := time.Now()
+
+	result := newTokenBucket(now, 5)
+
+	assert.Equal(t, &tokenBucket{
+		tokens: 5,
+		last:   now,
+	}, result)
+}
+
+func TestTokenBucketRefillPartial(t *testing.T) {
+	clock := newFakeClock()
+	obj := &tokenBucket{
+		tokens: 1,
+		last:   clock.Now(),
+	}
+	clock.Advance(2 * time.Second)
+
+	result := obj.refill(clock.Now(), 0.5, 5)
+
+	assert.False(t, result)
+	assert.Equal(t, &tokenBucket{
+		tokens: 2,
+		last:   clock.Now(),
+	}, obj)
+}
+
+func TestTokenBucketRefillFull(t *testing.T) {
+	clock := newFakeClock()
+	obj := &tokenBucket{
+		tokens: 1,
+		last:   clock.Now(),
+	}
+	clock.Advance(time.Minute)
+
+	result := obj.refill(clock.Now(), 0.5, 5)
+
+	assert.True(t, result)
+	assert.Equal(t, &tokenBucket{
+		tokens: 5,
+		last:   clock.Now(),
+	}, obj)
+}
+
+func TestTokenBucketRefillBackwards(t *testing.T) {
+	clock := newFakeClock()
+	obj := &tokenBucket{
+		tokens: 1,
+		last:   clock.Now(),
+	}
+	last := clock.Now()
+	clock.Advance(-time.Minute)
+
+	result := obj.refill(clock.Now(), 0.5, 5)
+
+	assert.False(t, result)
+	assert.Equal(t, &tokenBucket{
+		tokens: 1,
+		last:   last,
+	}, obj)
+}
+
+func TestDescribeInterval(t *testing.T) {
+	assert.Equal(t, "second", describeInterval(time.Second))
+	assert.Equal(t, "minute", describeInterval(time.Minute))
+	assert.Equal(t, "hour", describeInterval(time.Hour))
+	assert.Equal(t, "30s", describeInterval(30*time.Second))
+}
+
+func TestRateLimitingReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &RateLimitingReporter{})
+}
+
+func TestRateLimitPerKey(t *testing.T) {
+	obj := &RateLimitingReporter{}
+
+	opt := RateLimitPerKey(func(err error) string {
+		return "key"
+	}, 2, 3)
+	opt(obj)
+
+	assert.Equal(t, "key", obj.key(assert.AnError))
+	assert.Equal(t, rateLimit{limit: 2, burst: 3}, obj.perKey)
+}
+
+func TestRateLimitInterval(t *testing.T) {
+	obj := &RateLimitingReporter{}
+
+	opt := RateLimitInterval(time.Hour)
+	opt(obj)
+
+	assert.Equal(t, time.Hour, obj.interval)
+}
+
+func TestRateLimitClock(t *testing.T) {
+	clock := newFakeClock()
+	obj := &RateLimitingReporter{}
+
+	opt := RateLimitClock(clock.Now)
+	opt(obj)
+
+	assert.Equal(t, clock.Now(), obj.now())
+}
+
+func TestRateLimitTicker(t *testing.T) {
+	obj := &RateLimitingReporter{}
+
+	opt := RateLimitTicker(TimeTicker)
+	opt(obj)
+
+	assert.NotNil(t, obj.ticker)
+}
+
+func TestTimeTicker(t *testing.T) {
+	ticks, stop := TimeTicker(time.Millisecond)
+	defer stop()
+
+	select {
+	case <-ticks:
+	case <-time.After(time.Second):
+		assert.Fail(t, "ticker did not tick")
+	}
+}
+
+func TestNewRateLimitingReporterBase(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewRateLimitingReporter(rep, 10, 5)
+
+	assert.NotNil(t, result.now)
+	result.now = nil
+	assert.Equal(t, &RateLimitingReporter{
+		global:   rateLimit{limit: 10, burst: 5},
+		interval: time.Minute,
+		bucket: &tokenBucket{
+			tokens: 5,
+			last:   result.start,
+		},
+		buckets: map[string]*tokenBucket{},
+		start:   result.start,
+		rep:     rep,
+	}, result)
+}
+
+func TestNewRateLimitingReporterOptions(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *RateLimitingReporter
+	options := []RateLimitingReporterOption{
+		func(rr *RateLimitingReporter) {
+			opt1Called = rr
+		},
+		func(rr *RateLimitingReporter) {
+			opt2Called = rr
+		},
+		RateLimitClock(clock.Now),
+	}
+
+	result := NewRateLimitingReporter(rep, 10, 5, options...)
+
+	assert.Same(t, rep, result.rep)
+	assert.Equal(t, clock.Now(), result.start)
+	assert.Equal(t, &tokenBucket{
+		tokens: 5,
+		last:   clock.Now(),
+	}, result.bucket)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestRateLimitingReporterReportGlobal(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Times(3)
+	obj := NewRateLimitingReporter(rep, 1, 2, RateLimitClock(clock.Now))
+
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	clock.Advance(time.Second)
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, 2, obj.dropped)
+	assert.Equal(t, 2, obj.Suppressed())
+	rep.AssertExpectations(t)
+}
+
+func TestNewRateLimitingReporterClampsBurst(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewRateLimitingReporter(rep, 10, 0, RateLimitPerKey(func(err error) string {
+		return "key"
+	}, 1, -1))
+
+	assert.Equal(t, 1, result.global.burst)
+	assert.Equal(t, 1, result.perKey.burst)
+	assert.Equal(t, 1.0, result.bucket.tokens)
+}
+
+func TestRateLimitingReporterReportZeroBurst(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Twice()
+	obj := NewRateLimitingReporter(rep, 1, 0, RateLimitClock(clock.Now), RateLimitPerKey(func(err error) string {
+		return "key"
+	}, 1, 0))
+
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	clock.Advance(time.Second)
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, 1, obj.Suppressed())
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterReportPerKey(t *testing.T) {
+	clock := newFakeClock()
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	rep := &MockReporter{}
+	rep.On("Report", err1).Once()
+	rep.On("Report", err2).Once()
+	obj := NewRateLimitingReporter(rep, 0, 0, RateLimitClock(clock.Now), RateLimitPerKey(func(err error) string {
+		return err.Error()
+	}, 1, 1))
+
+	obj.Report(err1)
+	obj.Report(err1)
+	obj.Report(err2)
+	obj.Report(err2)
+
+	assert.Equal(t, 2, obj.Suppressed())
+	assert.Len(t, obj.buckets, 2)
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterReportPerKeyEmpty(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Times(3)
+	obj := NewRateLimitingReporter(rep, 0, 0, RateLimitClock(clock.Now), RateLimitPerKey(func(err error) string {
+		return ""
+	}, 1, 1))
+
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, 0, obj.Suppressed())
+	assert.Len(t, obj.buckets, 0)
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterReportGlobalAndPerKey(t *testing.T) {
+	clock := newFakeClock()
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	rep := &MockReporter{}
+	rep.On("Report", err1).Once()
+	obj := NewRateLimitingReporter(rep, 1, 1, RateLimitClock(clock.Now), RateLimitPerKey(func(err error) string {
+		return err.Error()
+	}, 1, 1))
+
+	obj.Report(err1)
+	obj.Report(err2)
+
+	assert.Equal(t, 1, obj.Suppressed())
+	assert.Equal(t, float64(1), obj.buckets["error 2"].tokens)
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterReportSummary(t *testing.T) {
+	clock := newFakeClock()
+	err := errors.New("final error")
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Once()
+	rep.On("Report", mock.MatchedBy(func(e error) bool {
+		return IsWarning(e) && e.Error() == "suppressed 3 diagnostics in the last minute"
+	})).Once()
+	rep.On("Report", err).Once()
+	obj := NewRateLimitingReporter(rep, 1.0/60, 1, RateLimitClock(clock.Now))
+
+	obj.Report(assert.AnError)
+	for i := 0; i < 3; i++ {
+		clock.Advance(10 * time.Second)
+		obj.Report(assert.AnError)
+	}
+	clock.Advance(5 * time.Minute)
+	obj.Report(err)
+
+	assert.Equal(t, 0, obj.dropped)
+	assert.Equal(t, 3, obj.Suppressed())
+	assert.Equal(t, clock.Now(), obj.start)
+	rep.AssertExpectations(t)
+	rep.AssertNumberOfCalls(t, "Report", 3)
+}
+
+func TestRateLimitingReporterReportSummaryNothingDropped(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Twice()
+	obj := NewRateLimitingReporter(rep, 1, 1, RateLimitClock(clock.Now))
+
+	obj.Report(assert.AnError)
+	clock.Advance(2 * time.Minute)
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, clock.Now(), obj.start)
+	rep.AssertExpectations(t)
+}
+
+// fakeTicker is a ticker for testing RateLimitingReporter.
+type fakeTicker struct {
+	ticks    chan time.Time
+	interval time.Duration
+	stopped  bool
+}
+
+// Start is a TickerFunc that starts the ticker.
+func (ft *fakeTicker) Start(d time.Duration) (<-chan time.Time, func()) {
+	ft.interval = d
+	return ft.ticks, func() {
+		ft.stopped = true
+	}
+}
+
+func TestRateLimitingReporterTicker(t *testing.T) {
+	clock := newFakeClock()
+	ticker := &fakeTicker{ticks: make(chan time.Time)}
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Once()
+	rep.On("Report", mock.MatchedBy(func(e error) bool {
+		return IsWarning(e) && e.Error() == "suppressed 2 diagnostics in the last minute"
+	})).Once()
+	obj := NewRateLimitingReporter(rep, 1.0/60, 1, RateLimitClock(clock.Now), RateLimitInterval(time.Minute), RateLimitTicker(ticker.Start))
+	for i := 0; i < 3; i++ {
+		obj.Report(assert.AnError)
+	}
+	clock.Advance(time.Minute)
+
+	ticker.ticks <- clock.Now()
+	ticker.ticks <- clock.Now()
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, ticker.interval)
+	assert.True(t, ticker.stopped)
+	assert.Nil(t, obj.stop)
+	assert.Equal(t, 0, obj.dropped)
+	assert.Equal(t, clock.Now(), obj.start)
+	rep.AssertExpectations(t)
+	rep.AssertNumberOfCalls(t, "Report", 2)
+}
+
+func TestRateLimitingReporterTickerPartial(t *testing.T) {
+	clock := newFakeClock()
+	ticker := &fakeTicker{ticks: make(chan time.Time)}
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Once()
+	rep.On("Report", mock.MatchedBy(func(e error) bool {
+		return IsWarning(e) && e.Error() == "suppressed 1 diagnostic in the last 30s"
+	})).Once()
+	obj := NewRateLimitingReporter(rep, 1.0/60, 1, RateLimitClock(clock.Now), RateLimitTicker(ticker.Start))
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	clock.Advance(30 * time.Second)
+
+	ticker.ticks <- clock.Now()
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterTickerCloseTwice(t *testing.T) {
+	ticker := &fakeTicker{ticks: make(chan time.Time)}
+	rep := &MockReporter{}
+	obj := NewRateLimitingReporter(rep, 1, 1, RateLimitTicker(ticker.Start))
+
+	assert.NoError(t, obj.Close())
+	assert.NoError(t, obj.Close())
+
+	assert.True(t, ticker.stopped)
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterReportPrunesBuckets(t *testing.T) {
+	clock := newFakeClock()
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	rep := &MockReporter{}
+	rep.On("Report", err1).Once()
+	rep.On("Report", err2).Once()
+	obj := NewRateLimitingReporter(rep, 0, 0, RateLimitClock(clock.Now), RateLimitPerKey(func(err error) string {
+		return err.Error()
+	}, 1, 1))
+
+	obj.Report(err1)
+	clock.Advance(time.Minute)
+	obj.Report(err2)
+
+	assert.Equal(t, []string{"error 2"}, func() []string {
+		keys := []string{}
+		for key := range obj.buckets {
+			keys = append(keys, key)
+		}
+		return keys
+	}())
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterReportConcurrent(t *testing.T) {
+	clock := newFakeClock()
+	counter := NewCountingReporter(Root())
+	obj := NewRateLimitingReporter(counter, 1, 10, RateLimitClock(clock.Now))
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				obj.Report(assert.AnError)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, counter.Errors())
+	assert.Equal(t, 990, obj.Suppressed())
+}
+
+func TestRateLimitingReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &RateLimitingReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestRateLimitingReporterSuppressed(t *testing.T) {
+	obj := &RateLimitingReporter{
+		suppressed: 42,
+	}
+
+	result := obj.Suppressed()
+
+	assert.Equal(t, 42, result)
+}
+
+func TestRateLimitingReporterClose(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError).Once()
+	rep.On("Report", mock.MatchedBy(func(e error) bool {
+		return IsWarning(e) && e.Error() == "suppressed 1 diagnostic in the last 1.5s"
+	})).Once()
+	obj := NewRateLimitingReporter(rep, 0.1, 1, RateLimitClock(clock.Now))
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	clock.Advance(1500 * time.Millisecond)
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, obj.dropped)
+	rep.AssertExpectations(t)
+}
+
+func TestRateLimitingReporterCloseNothingDropped(t *testing.T) {
+	clock := newFakeClock()
+	rep := &MockReporter{}
+	obj := NewRateLimitingReporter(rep, 1, 1, RateLimitClock(clock.Now))
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	rep.AssertExpectations(t)
+}
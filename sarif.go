@@ -0,0 +1,342 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sync"
+)
+
+// Constants describing the SARIF log format.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifMessage describes a SARIF message object.
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifConfiguration describes a SARIF reportingConfiguration object.
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+// sarifRule describes a SARIF reportingDescriptor object.
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	ShortDescription     *sarifMessage       `json:"shortDescription,omitempty"`
+	FullDescription      *sarifMessage       `json:"fullDescription,omitempty"`
+	HelpURI              string              `json:"helpUri,omitempty"`
+	DefaultConfiguration *sarifConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+// sarifDriver describes a SARIF toolComponent object.
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules,omitempty"`
+}
+
+// sarifTool describes a SARIF tool object.
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+// sarifArtifactLocation describes a SARIF artifactLocation object.
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion describes a SARIF region object.
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// sarifPhysicalLocation describes a SARIF physicalLocation object.
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+// sarifLogicalLocation describes a SARIF logicalLocation object.
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLocation describes a SARIF location object.
+type sarifLocation struct {
+	ID               *int                   `json:"id,omitempty"`
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+// sarifResult describes a SARIF result object.
+type sarifResult struct {
+	RuleID           string          `json:"ruleId,omitempty"`
+	RuleIndex        *int            `json:"ruleIndex,omitempty"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+}
+
+// sarifRun describes a SARIF run object.
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+// sarifLog describes the top-level SARIF log object.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifLevel maps a severity to a SARIF result level.
+func sarifLevel(sev Severity) string {
+	switch {
+	case sev >= SeverityError:
+		return "error"
+	case sev == SeverityWarning:
+		return "warning"
+	case sev >= SeverityInfo:
+		return "note"
+	}
+
+	return "none"
+}
+
+// sarifPhysical is a helper that constructs a SARIF physicalLocation
+// from a Position.  It returns nil if the position has no file name.
+func sarifPhysical(pos Position) *sarifPhysicalLocation {
+	if pos.File == "" {
+		return nil
+	}
+
+	loc := &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{
+			URI: (&url.URL{Path: filepath.ToSlash(pos.File)}).String(),
+		},
+	}
+	if pos.IsValid() {
+		loc.Region = &sarifRegion{
+			StartLine:   pos.Line,
+			StartColumn: pos.Column,
+		}
+		if pos.End != nil && pos.End.IsValid() {
+			loc.Region.EndLine = pos.End.Line
+			loc.Region.EndColumn = pos.End.Column
+		}
+	}
+
+	return loc
+}
+
+// SARIFReporter is a Reporter that accumulates the errors and
+// warnings reported using it, and writes them to a specified
+// io.Writer stream as a SARIF 2.1.0 log when it is closed.
+type SARIFReporter struct {
+	sync.Mutex
+
+	out     io.Writer // The output stream to write to
+	name    string    // The name of the tool
+	version string    // The version of the tool
+	reg     *Registry // Registry for describing rules
+	diags   []error   // The reported errors
+	closed  bool      // Set when the log has been written
+	rep     Reporter  // Child reporter
+}
+
+// SARIFReporterOption describes an option for a SARIFReporter.
+type SARIFReporterOption func(*SARIFReporter)
+
+// SARIFTool specifies the name and version of the tool producing the
+// SARIF log.  The default tool name is "kent", with no version.
+func SARIFTool(name, version string) SARIFReporterOption {
+	return func(sr *SARIFReporter) {
+		sr.name = name
+		sr.version = version
+	}
+}
+
+// SARIFRegistry specifies a Registry to use for describing the rules
+// derived from the diagnostic codes of the reported errors.  The
+// title, explanation, help URL, and default severity of each code
+// are included in the rule descriptions.
+func SARIFRegistry(reg *Registry) SARIFReporterOption {
+	return func(sr *SARIFReporter) {
+		sr.reg = reg
+	}
+}
+
+// NewSARIFReporter constructs a new SARIF reporter.  A SARIF reporter
+// accumulates the reported errors and warnings, and writes a SARIF
+// 2.1.0 log to the specified output stream when its Close method is
+// called, such as by the Close helper.  The log contains a single
+// run, with rules derived from the diagnostic codes of the errors
+// and results with levels derived from their severities.
+func NewSARIFReporter(out io.Writer, rep Reporter, options ...SARIFReporterOption) *SARIFReporter {
+	obj := &SARIFReporter{
+		out:  out,
+		name: "kent",
+		rep:  rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (sr *SARIFReporter) Report(err error) {
+	sr.Lock()
+	sr.diags = append(sr.diags, err)
+	sr.Unlock()
+
+	sr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (sr *SARIFReporter) Unwrap() []Reporter {
+	return []Reporter{sr.rep}
+}
+
+// rule is a helper that constructs the rule description for a
+// diagnostic code.
+func (sr *SARIFReporter) rule(code string) sarifRule {
+	rule := sarifRule{
+		ID: code,
+	}
+	if sr.reg == nil {
+		return rule
+	}
+
+	if info, ok := sr.reg.Lookup(code); ok {
+		if info.Title != "" {
+			rule.ShortDescription = &sarifMessage{Text: info.Title}
+		}
+		if info.Explanation != "" {
+			rule.FullDescription = &sarifMessage{Text: info.Explanation}
+		}
+		rule.HelpURI = info.HelpURL
+		rule.DefaultConfiguration = &sarifConfiguration{
+			Level: sarifLevel(info.Severity),
+		}
+	}
+
+	return rule
+}
+
+// result is a helper that constructs the result for an error.  The
+// rules list is extended if the error has a previously unseen
+// diagnostic code.
+func (sr *SARIFReporter) result(err error, driver *sarifDriver, ruleIdx map[string]int) sarifResult {
+	res := sarifResult{
+		Level:   sarifLevel(SeverityOf(err)),
+		Message: sarifMessage{Text: err.Error()},
+	}
+
+	// Describe the rule
+	if code, ok := CodeOf(err); ok {
+		idx, ok := ruleIdx[code]
+		if !ok {
+			idx = len(driver.Rules)
+			ruleIdx[code] = idx
+			driver.Rules = append(driver.Rules, sr.rule(code))
+		}
+		res.RuleID = code
+		res.RuleIndex = &idx
+	}
+
+	// Describe the location
+	loc := sarifLocation{}
+	if pos, ok := PositionOf(err); ok {
+		loc.PhysicalLocation = sarifPhysical(pos)
+	}
+	if scope := Scope(err); len(scope) > 0 {
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: joinScope(scope)}}
+	}
+	if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+		res.Locations = []sarifLocation{loc}
+	}
+
+	// Describe the related locations
+	for i, note := range Notes(err) {
+		id := i
+		res.RelatedLocations = append(res.RelatedLocations, sarifLocation{
+			ID:               &id,
+			PhysicalLocation: sarifPhysical(note.Position),
+			Message:          &sarifMessage{Text: note.Message},
+		})
+	}
+
+	return res
+}
+
+// Close writes the SARIF log to the output stream.  The log is only
+// written once; subsequent calls to Close do nothing.
+func (sr *SARIFReporter) Close() error {
+	// Lock the mutex for thread safety
+	sr.Lock()
+	defer sr.Unlock()
+
+	if sr.closed {
+		return nil
+	}
+	sr.closed = true
+
+	// Construct the run
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:    sr.name,
+				Version: sr.version,
+			},
+		},
+		Results: []sarifResult{},
+	}
+	ruleIdx := map[string]int{}
+	for _, err := range sr.diags {
+		run.Results = append(run.Results, sr.result(err, &run.Tool.Driver, ruleIdx))
+	}
+
+	// Write the log
+	enc := json.NewEncoder(sr.out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(&sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,526 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSARIFSchema loads the excerpt of the SARIF 2.1.0 JSON schema
+// from the testdata directory.
+func loadSARIFSchema(t *testing.T) map[string]interface{} {
+	data, err := os.ReadFile("testdata/sarif-2.1.0-excerpt.json")
+	require.NoError(t, err)
+	schema := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	return schema
+}
+
+// schemaType checks whether a decoded JSON value has the specified
+// JSON schema type.
+func schemaType(typ string, value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case string:
+		return typ == "string"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == math.Trunc(v))
+	case []interface{}:
+		return typ == "array"
+	case map[string]interface{}:
+		return typ == "object"
+	}
+
+	return false
+}
+
+// validateSchema validates a decoded JSON value against a JSON
+// schema.  It supports only the keywords used by the excerpt of the
+// SARIF schema in the testdata directory.
+func validateSchema(root, schema map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs := root["definitions"].(map[string]interface{})
+		return validateSchema(root, defs[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{}), value, path)
+	}
+
+	// Check the type
+	switch typ := schema["type"].(type) {
+	case string:
+		if !schemaType(typ, value) {
+			return fmt.Errorf("%s: not of type %s", path, typ)
+		}
+	case []interface{}:
+		ok := false
+		for _, tmp := range typ {
+			ok = ok || schemaType(tmp.(string), value)
+		}
+		if !ok {
+			return fmt.Errorf("%s: not of type %v", path, typ)
+		}
+	}
+
+	// Check the value
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, item := range enum {
+			found = found || item == value
+		}
+		if !found {
+			return fmt.Errorf("%s: %v not in %v", path, value, enum)
+		}
+	}
+	if n, ok := value.(float64); ok {
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			return fmt.Errorf("%s: %v less than %v", path, n, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			return fmt.Errorf("%s: %v greater than %v", path, n, max)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		errs := []error{}
+		for _, sub := range anyOf {
+			if err := validateSchema(root, sub.(map[string]interface{}), value, path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) == len(anyOf) {
+			return fmt.Errorf("%s: no alternative matched: %w", path, errors.Join(errs...))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, key := range required {
+				if _, ok := v[key.(string)]; !ok {
+					return fmt.Errorf("%s: missing required property %q", path, key)
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for key, item := range v {
+			sub, ok := props[key].(map[string]interface{})
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+				continue
+			}
+			if err := validateSchema(root, sub, item, path+"."+key); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			return fmt.Errorf("%s: fewer than %v items", path, min)
+		}
+		seen := map[string]bool{}
+		for i, item := range v {
+			if schema["uniqueItems"] == true {
+				data, _ := json.Marshal(item)
+				if seen[string(data)] {
+					return fmt.Errorf("%s[%d]: duplicate item", path, i)
+				}
+				seen[string(data)] = true
+			}
+			if sub, ok := schema["items"].(map[string]interface{}); ok {
+				if err := validateSchema(root, sub, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// assertSARIFValid asserts that a SARIF log is valid according to the
+// excerpt of the SARIF 2.1.0 schema.
+func assertSARIFValid(t *testing.T, data []byte) {
+	var log interface{}
+	require.NoError(t, json.Unmarshal(data, &log))
+	schema := loadSARIFSchema(t)
+
+	assert.NoError(t, validateSchema(schema, schema, log, "$"))
+}
+
+func TestValidateSchemaInvalid(t *testing.T) {
+	schema := loadSARIFSchema(t)
+	tests := map[string]string{
+		"bad version":     `{"version": "2.0.0", "runs": []}`,
+		"missing runs":    `{"version": "2.1.0"}`,
+		"extra property":  `{"version": "2.1.0", "runs": [], "bogus": 1}`,
+		"missing tool":    `{"version": "2.1.0", "runs": [{"results": []}]}`,
+		"missing name":    `{"version": "2.1.0", "runs": [{"tool": {"driver": {}}}]}`,
+		"bad level":       `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"level": "fatal", "message": {"text": "m"}}]}]}`,
+		"missing message": `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"level": "error"}]}]}`,
+		"empty message":   `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {}}]}]}`,
+		"zero line":       `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {"text": "m"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "f"}, "region": {"startLine": 0}}}]}]}]}`,
+		"no artifact":     `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {"text": "m"}, "locations": [{"physicalLocation": {"region": {"startLine": 1}}}]}]}]}`,
+		"duplicate rules": `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x", "rules": [{"id": "A"}, {"id": "A"}]}}}]}`,
+		"bad rule index":  `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"ruleIndex": 1.5, "message": {"text": "m"}}]}]}`,
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			var log interface{}
+			require.NoError(t, json.Unmarshal([]byte(text), &log))
+
+			err := validateSchema(schema, schema, log, "$")
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSARIFLevel(t *testing.T) {
+	assert.Equal(t, "none", sarifLevel(SeverityDebug))
+	assert.Equal(t, "note", sarifLevel(SeverityInfo))
+	assert.Equal(t, "note", sarifLevel(SeverityNotice))
+	assert.Equal(t, "warning", sarifLevel(SeverityWarning))
+	assert.Equal(t, "error", sarifLevel(SeverityError))
+	assert.Equal(t, "error", sarifLevel(SeverityFatal))
+}
+
+func TestSARIFPhysicalNoFile(t *testing.T) {
+	result := sarifPhysical(Position{Line: 3})
+
+	assert.Nil(t, result)
+}
+
+func TestSARIFPhysicalFileOnly(t *testing.T) {
+	result := sarifPhysical(Position{File: "dir/my file.cfg"})
+
+	assert.Equal(t, &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: "dir/my%20file.cfg"},
+	}, result)
+}
+
+func TestSARIFPhysicalRange(t *testing.T) {
+	result := sarifPhysical(Position{
+		File:   "file.cfg",
+		Line:   3,
+		Column: 5,
+		End:    &Position{Line: 4, Column: 2},
+	})
+
+	assert.Equal(t, &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: "file.cfg"},
+		Region: &sarifRegion{
+			StartLine:   3,
+			StartColumn: 5,
+			EndLine:     4,
+			EndColumn:   2,
+		},
+	}, result)
+}
+
+func TestSARIFReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &SARIFReporter{})
+}
+
+func TestSARIFTool(t *testing.T) {
+	obj := &SARIFReporter{}
+
+	opt := SARIFTool("linter", "1.2.3")
+	opt(obj)
+
+	assert.Equal(t, &SARIFReporter{
+		name:    "linter",
+		version: "1.2.3",
+	}, obj)
+}
+
+func TestSARIFRegistry(t *testing.T) {
+	reg := NewRegistry()
+	obj := &SARIFReporter{}
+
+	opt := SARIFRegistry(reg)
+	opt(obj)
+
+	assert.Same(t, reg, obj.reg)
+}
+
+func TestNewSARIFReporterBase(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+
+	result := NewSARIFReporter(out, rep)
+
+	assert.Equal(t, &SARIFReporter{
+		out:  out,
+		name: "kent",
+		rep:  rep,
+	}, result)
+}
+
+func TestNewSARIFReporterOptions(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *SARIFReporter
+	options := []SARIFReporterOption{
+		func(sr *SARIFReporter) {
+			opt1Called = sr
+		},
+		func(sr *SARIFReporter) {
+			opt2Called = sr
+		},
+	}
+
+	result := NewSARIFReporter(out, rep, options...)
+
+	assert.Equal(t, &SARIFReporter{
+		out:  out,
+		name: "kent",
+		rep:  rep,
+	}, result)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestSARIFReporterReport(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", assert.AnError)
+	obj := &SARIFReporter{
+		rep: rep,
+	}
+
+	obj.Report(assert.AnError)
+
+	assert.Equal(t, []error{assert.AnError}, obj.diags)
+	rep.AssertExpectations(t)
+}
+
+func TestSARIFReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &SARIFReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestSARIFReporterRuleNoRegistry(t *testing.T) {
+	obj := &SARIFReporter{}
+
+	result := obj.rule("CFG1003")
+
+	assert.Equal(t, sarifRule{ID: "CFG1003"}, result)
+}
+
+func TestSARIFReporterRuleRegistered(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.Register(CodeInfo{
+		Code:        "CFG1003",
+		Title:       "Duplicate key",
+		Severity:    SeverityWarning,
+		Explanation: "A key was defined twice.",
+		HelpURL:     "https://example.com/CFG1003",
+	}))
+	obj := &SARIFReporter{
+		reg: reg,
+	}
+
+	result := obj.rule("CFG1003")
+
+	assert.Equal(t, sarifRule{
+		ID:                   "CFG1003",
+		ShortDescription:     &sarifMessage{Text: "Duplicate key"},
+		FullDescription:      &sarifMessage{Text: "A key was defined twice."},
+		HelpURI:              "https://example.com/CFG1003",
+		DefaultConfiguration: &sarifConfiguration{Level: "warning"},
+	}, result)
+}
+
+func TestSARIFReporterRuleUnregistered(t *testing.T) {
+	obj := &SARIFReporter{
+		reg: NewRegistry(),
+	}
+
+	result := obj.rule("CFG1003")
+
+	assert.Equal(t, sarifRule{ID: "CFG1003"}, result)
+}
+
+func TestSARIFReporterClose(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSARIFReporter(out, Root(), SARIFTool("linter", "1.2.3"))
+	obj.Report(WithCode(ErrorAt(Position{File: "file.cfg", Line: 3, Column: 5}, "test error"), "CFG1001"))
+	obj.Report(WithNotes(WithCode(NewWarning("test warning"), "CFG1002"), NoteAt(Position{File: "file.cfg", Line: 1}, "defined here")))
+	obj.Report(WithScope(WithCode(NewSeverity(SeverityNotice, "test notice"), "CFG1001"), "deploy", "web"))
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	expected := `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "linter",
+          "version": "1.2.3",
+          "rules": [
+            {
+              "id": "CFG1001"
+            },
+            {
+              "id": "CFG1002"
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "CFG1001",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "test error"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "file.cfg"
+                },
+                "region": {
+                  "startLine": 3,
+                  "startColumn": 5
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "CFG1002",
+          "ruleIndex": 1,
+          "level": "warning",
+          "message": {
+            "text": "test warning"
+          },
+          "relatedLocations": [
+            {
+              "id": 0,
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "file.cfg"
+                },
+                "region": {
+                  "startLine": 1
+                }
+              },
+              "message": {
+                "text": "defined here"
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "CFG1001",
+          "ruleIndex": 0,
+          "level": "note",
+          "message": {
+            "text": "test notice"
+          },
+          "locations": [
+            {
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "deploy > web"
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+	assert.Equal(t, expected, out.String())
+	assertSARIFValid(t, []byte(expected))
+	assertSARIFValid(t, out.Bytes())
+}
+
+func TestSARIFReporterCloseSchema(t *testing.T) {
+	reg := NewRegistry()
+	require.NoError(t, reg.Register(CodeInfo{
+		Code:        "CFG1003",
+		Title:       "Duplicate key",
+		Severity:    SeverityWarning,
+		Explanation: "A key was defined twice.",
+		HelpURL:     "https://example.com/CFG1003",
+	}))
+	out := &bytes.Buffer{}
+	obj := NewSARIFReporter(out, Root(), SARIFRegistry(reg))
+	end := Position{Line: 4, Column: 2}
+	obj.Report(WithCode(ErrorAt(Position{File: "dir/file name.cfg", Line: 3, Column: 5, End: &end}, "test error"), "CFG1003"))
+	obj.Report(WithNotes(NewSeverity(SeverityDebug, "test debug"), Notef("unpositioned note"), NoteAt(Position{File: "other.cfg"}, "file note")))
+	obj.Report(WithPosition(NewFatal("test fatal"), Position{File: "file.cfg"}))
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assertSARIFValid(t, out.Bytes())
+}
+
+func TestSARIFReporterCloseEmpty(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSARIFReporter(out, Root())
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	log := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log["version"])
+	runs := log["runs"].([]interface{})
+	require.Len(t, runs, 1)
+	assert.Equal(t, []interface{}{}, runs[0].(map[string]interface{})["results"])
+	assertSARIFValid(t, out.Bytes())
+}
+
+func TestSARIFReporterCloseTwice(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := &SARIFReporter{
+		out:    out,
+		closed: true,
+	}
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+}
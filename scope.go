@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import "strings"
+
+// ScopeSeparator is the separator used between the elements of a
+// scope path when it is rendered as a string.
+const ScopeSeparator = " > "
+
+// Scoped is a utility interface for errors that carry a hierarchical
+// scope, such as the section of a document in which the error was
+// found.  The Scope helper collects the scope from all errors in an
+// error chain that implement Scoped.
+type Scoped interface {
+	error
+
+	// Scope returns the scope path attached to the error.
+	Scope() []string
+}
+
+// scopedError is an implementation of Scoped that wraps another
+// error.
+type scopedError struct {
+	scope []string // The scope path
+	err   error    // The wrapped error
+}
+
+// Error returns the error message.
+func (se *scopedError) Error() string {
+	return se.err.Error()
+}
+
+// Scope returns the scope path attached to the error.
+func (se *scopedError) Scope() []string {
+	return se.scope
+}
+
+// Unwrap returns the wrapped error.
+func (se *scopedError) Unwrap() error {
+	return se.err
+}
+
+// WithScope attaches a scope path to an error.  The error message is
+// not altered.
+func WithScope(err error, scope ...string) error {
+	return &scopedError{
+		scope: scope,
+		err:   err,
+	}
+}
+
+// Scope retrieves the full scope path associated with an error.  It
+// follows the entire error chain and concatenates the scope paths
+// from every error implementing Scoped, with the scope from outer
+// errors preceding the scope from inner errors.  If the error has no
+// scope, nil is returned.
+func Scope(err error) []string {
+	var scope []string
+	walk(err, func(e error) bool {
+		if se, ok := e.(Scoped); ok {
+			scope = append(scope, se.Scope()...)
+		}
+
+		return false
+	})
+
+	return scope
+}
+
+// ScopedReporter is a Reporter that tags every error reported through
+// it with a scope name before passing it on to its parent.  Scoped
+// reporters may be nested, in which case the scope names compose into
+// a scope path, e.g., "deploy > services > web".
+type ScopedReporter struct {
+	name string   // The name of the scope
+	rep  Reporter // Parent reporter
+}
+
+// NewScopedReporter constructs a new ScopedReporter.  A scoped
+// reporter attaches the scope name to every error reported through
+// it (see WithScope) before passing the error on to the parent
+// reporter.  If the parent reporter is itself a scoped reporter, or
+// wraps one, the scopes compose, with the parent's scope name
+// preceding this reporter's scope name in the path returned by
+// Scope.
+func NewScopedReporter(parent Reporter, name string) *ScopedReporter {
+	return &ScopedReporter{
+		name: name,
+		rep:  parent,
+	}
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (sr *ScopedReporter) Report(err error) {
+	sr.rep.Report(WithScope(err, sr.name))
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (sr *ScopedReporter) Unwrap() []Reporter {
+	return []Reporter{sr.rep}
+}
+
+// Name returns the name of the scope.
+func (sr *ScopedReporter) Name() string {
+	return sr.name
+}
+
+// joinScope is a helper that renders a scope path as a string.
+func joinScope(scope []string) string {
+	return strings.Join(scope, ScopeSeparator)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScopedErrorImplementsScoped(t *testing.T) {
+	assert.Implements(t, (*Scoped)(nil), &scopedError{})
+}
+
+func TestScopedErrorError(t *testing.T) {
+	obj := &scopedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestScopedErrorScope(t *testing.T) {
+	obj := &scopedError{
+		scope: []string{"deploy", "services"},
+	}
+
+	result := obj.Scope()
+
+	assert.Equal(t, []string{"deploy", "services"}, result)
+}
+
+func TestScopedErrorUnwrap(t *testing.T) {
+	obj := &scopedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestWithScope(t *testing.T) {
+	result := WithScope(assert.AnError, "deploy", "services")
+
+	assert.Equal(t, &scopedError{
+		scope: []string{"deploy", "services"},
+		err:   assert.AnError,
+	}, result)
+}
+
+func TestScopeChain(t *testing.T) {
+	err := WithScope(fmt.Errorf("wrapped: %w", WithScope(assert.AnError, "web")), "deploy", "services")
+
+	result := Scope(err)
+
+	assert.Equal(t, []string{"deploy", "services", "web"}, result)
+}
+
+func TestScopeMissing(t *testing.T) {
+	result := Scope(assert.AnError)
+
+	assert.Nil(t, result)
+}
+
+func TestScopedReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &ScopedReporter{})
+}
+
+func TestNewScopedReporter(t *testing.T) {
+	rep := &MockReporter{}
+
+	result := NewScopedReporter(rep, "deploy")
+
+	assert.Equal(t, &ScopedReporter{
+		name: "deploy",
+		rep:  rep,
+	}, result)
+}
+
+func TestScopedReporterReport(t *testing.T) {
+	rep := &MockReporter{}
+	rep.On("Report", mock.Anything)
+	obj := &ScopedReporter{
+		name: "deploy",
+		rep:  rep,
+	}
+
+	obj.Report(assert.AnError)
+
+	rep.AssertExpectations(t)
+	err := rep.Calls[0].Arguments.Error(0)
+	assert.Equal(t, &scopedError{
+		scope: []string{"deploy"},
+		err:   assert.AnError,
+	}, err)
+}
+
+func TestScopedReporterReportNested(t *testing.T) {
+	out := &bytes.Buffer{}
+	capt := NewCapturingReporter(NewWritingReporter(out, Root()))
+	obj := NewScopedReporter(NewScopedReporter(NewScopedReporter(capt, "deploy"), "services"), "web")
+
+	obj.Report(NewWarning("test warning"))
+
+	assert.Equal(t, "deploy > services > web: WARNING: test warning\n", out.String())
+	assert.Len(t, capt.List(), 1)
+	assert.Equal(t, []string{"deploy", "services", "web"}, Scope(capt.List()[0]))
+	assert.True(t, IsWarning(capt.List()[0]))
+}
+
+func TestScopedReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &ScopedReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestScopedReporterAs(t *testing.T) {
+	inner := NewScopedReporter(Root(), "deploy")
+	obj := NewCountingReporter(inner)
+
+	var target *ScopedReporter
+	result := As(obj, &target)
+
+	assert.True(t, result)
+	assert.Same(t, inner, target)
+}
+
+func TestScopedReporterName(t *testing.T) {
+	obj := &ScopedReporter{
+		name: "deploy",
+	}
+
+	result := obj.Name()
+
+	assert.Equal(t, "deploy", result)
+}
+
+func TestJoinScope(t *testing.T) {
+	result := joinScope([]string{"deploy", "services", "web"})
+
+	assert.Equal(t, "deploy > services > web", result)
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownSeverity is returned when a severity is not recognized.
+var ErrUnknownSeverity = errors.New("unknown severity")
+
+// Severity describes the severity of a reported error.  Severities
+// are ordered, so a Severity may be compared against another to
+// determine which is more severe.
+type Severity int
+
+// Recognized severities, in increasing order of severity.
+const (
+	SeverityDebug   Severity = iota // Debugging information
+	SeverityInfo                    // Informational message
+	SeverityNotice                  // Normal but significant condition
+	SeverityWarning                 // Warning condition
+	SeverityError                   // Error condition
+	SeverityFatal                   // Unrecoverable error condition
+
+	numSeverities = int(SeverityFatal) + 1 // Number of severities
+)
+
+// severityNames maps severities to their names.
+var severityNames = [numSeverities]string{
+	SeverityDebug:   "debug",
+	SeverityInfo:    "info",
+	SeverityNotice:  "notice",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+	SeverityFatal:   "fatal",
+}
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	if !s.valid() {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+
+	return severityNames[s]
+}
+
+// MarshalText implements encoding.TextMarshaler.  It returns the
+// name of the severity.
+func (s Severity) MarshalText() ([]byte, error) {
+	if !s.valid() {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSeverity, int(s))
+	}
+
+	return []byte(severityNames[s]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.  It accepts the
+// name of a severity, as returned by String.
+func (s *Severity) UnmarshalText(text []byte) error {
+	sev, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = sev
+
+	return nil
+}
+
+// ParseSeverity parses the name of a severity, as returned by
+// String, and returns the severity.  Case is ignored.  If the name is
+// not recognized, an error wrapping ErrUnknownSeverity is returned.
+func ParseSeverity(name string) (Severity, error) {
+	for sev, sevName := range severityNames {
+		if strings.EqualFold(name, sevName) {
+			return Severity(sev), nil
+		}
+	}
+
+	return SeverityError, fmt.Errorf("%w: %q", ErrUnknownSeverity, name)
+}
+
+// valid is a helper that checks if a severity is one of the
+// recognized severities.
+func (s Severity) valid() bool {
+	return s >= SeverityDebug && s <= SeverityFatal
+}
+
+// clamp is a helper that forces a severity into the range of
+// recognized severities.
+func (s Severity) clamp() Severity {
+	if s < SeverityDebug {
+		return SeverityDebug
+	} else if s > SeverityFatal {
+		return SeverityFatal
+	}
+
+	return s
+}
+
+// Leveled is a utility interface for errors that carry an explicit
+// severity.  An object implementing Leveled implements the error
+// interface with a Severity method.
+type Leveled interface {
+	error
+
+	// Severity returns the severity of the error.
+	Severity() Severity
+}
+
+// severityError is an implementation of Leveled that is equivalent
+// to that used by errors.New.
+type severityError struct {
+	sev Severity // The severity
+	msg string   // The error message
+	err error    // The wrapped error
+}
+
+// Error returns the error message.
+func (se *severityError) Error() string {
+	return se.msg
+}
+
+// Severity returns the severity of the error.
+func (se *severityError) Severity() Severity {
+	return se.sev
+}
+
+// Unwrap returns the wrapped error, if there is one.
+func (se *severityError) Unwrap() error {
+	return se.err
+}
+
+// multiSeverityError is an implementation of Leveled that wraps
+// multiple errors, as constructed by Severityf with multiple %w
+// verbs.
+type multiSeverityError struct {
+	sev  Severity // The severity
+	msg  string   // The error message
+	errs []error  // The wrapped errors
+}
+
+// Error returns the error message.
+func (mse *multiSeverityError) Error() string {
+	return mse.msg
+}
+
+// Severity returns the severity of the error.
+func (mse *multiSeverityError) Severity() Severity {
+	return mse.sev
+}
+
+// Unwrap returns the wrapped errors.
+func (mse *multiSeverityError) Unwrap() []error {
+	return mse.errs
+}
+
+// NewSeverity constructs a new simple error with the specified
+// severity.  It is the equivalent of errors.New for errors with a
+// severity.
+func NewSeverity(sev Severity, text string) error {
+	return &severityError{
+		sev: sev,
+		msg: text,
+	}
+}
+
+// SeverityWrap wraps an error with the specified severity.
+func SeverityWrap(sev Severity, err error) error {
+	return &severityError{
+		sev: sev,
+		msg: err.Error(),
+		err: err,
+	}
+}
+
+// Severityf constructs a new error with the specified severity,
+// potentially wrapping other errors.  It is the equivalent of
+// fmt.Errorf for errors with a severity; like fmt.Errorf, if the
+// format contains multiple %w verbs, all the wrapped errors are
+// preserved.
+func Severityf(sev Severity, format string, args ...interface{}) error {
+	// Use Errorf to do the work
+	tmp := fmt.Errorf(format, args...) //nolint:goerr113
+
+	// Now give it a severity
+	if multi, ok := tmp.(interface{ Unwrap() []error }); ok {
+		return &multiSeverityError{
+			sev:  sev,
+			msg:  tmp.Error(),
+			errs: multi.Unwrap(),
+		}
+	}
+
+	return &severityError{
+		sev: sev,
+		msg: tmp.Error(),
+		err: errors.Unwrap(tmp),
+	}
+}
+
+// SeverityOf determines the severity of an error.  It follows the
+// chain of wrapped errors and returns the severity of the first error
+// implementing Leveled; an error implementing Warning is treated as
+// having SeverityWarning.  If no severity can be found, SeverityError
+// is returned.  For an error wrapping multiple errors, such as one
+// constructed by errors.Join, the highest severity of the wrapped
+// errors is returned, so that a joined error is only a warning if
+// all the joined errors are warnings.
+func SeverityOf(err error) Severity {
+	for err != nil {
+		switch tmp := err.(type) {
+		case Leveled:
+			return tmp.Severity().clamp()
+
+		case Warning:
+			return SeverityWarning
+		}
+
+		switch tmp := err.(type) {
+		case interface{ Unwrap() error }:
+			err = tmp.Unwrap()
+
+		case interface{ Unwrap() []error }:
+			errs := tmp.Unwrap()
+			if len(errs) == 0 {
+				return SeverityError
+			}
+
+			// Use the highest severity of the wrapped errors
+			sev := SeverityDebug
+			for _, e := range errs {
+				if tmp := SeverityOf(e); tmp > sev {
+					sev = tmp
+				}
+			}
+
+			return sev
+
+		default:
+			return SeverityError
+		}
+	}
+
+	return SeverityError
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityString(t *testing.T) {
+	assert.Equal(t, "debug", SeverityDebug.String())
+	assert.Equal(t, "info", SeverityInfo.String())
+	assert.Equal(t, "notice", SeverityNotice.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "fatal", SeverityFatal.String())
+}
+
+func TestSeverityStringInvalid(t *testing.T) {
+	assert.Equal(t, "Severity(42)", Severity(42).String())
+	assert.Equal(t, "Severity(-1)", Severity(-1).String())
+}
+
+func TestSeverityMarshalText(t *testing.T) {
+	result, err := SeverityNotice.MarshalText()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("notice"), result)
+}
+
+func TestSeverityMarshalTextInvalid(t *testing.T) {
+	result, err := Severity(42).MarshalText()
+
+	assert.True(t, errors.Is(err, ErrUnknownSeverity))
+	assert.Nil(t, result)
+}
+
+func TestSeverityUnmarshalText(t *testing.T) {
+	sev := SeverityError
+
+	err := sev.UnmarshalText([]byte("notice"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityNotice, sev)
+}
+
+func TestSeverityUnmarshalTextInvalid(t *testing.T) {
+	sev := SeverityNotice
+
+	err := sev.UnmarshalText([]byte("bogus"))
+
+	assert.True(t, errors.Is(err, ErrUnknownSeverity))
+	assert.Equal(t, SeverityNotice, sev)
+}
+
+func TestParseSeverity(t *testing.T) {
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		result, err := ParseSeverity(sev.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, sev, result)
+	}
+}
+
+func TestParseSeverityCase(t *testing.T) {
+	result, err := ParseSeverity("WARNING")
+
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityWarning, result)
+}
+
+func TestParseSeverityUnknown(t *testing.T) {
+	result, err := ParseSeverity("bogus")
+
+	assert.True(t, errors.Is(err, ErrUnknownSeverity))
+	assert.EqualError(t, err, `unknown severity: "bogus"`)
+	assert.Equal(t, SeverityError, result)
+}
+
+func TestSeverityClamp(t *testing.T) {
+	assert.Equal(t, SeverityDebug, Severity(-1).clamp())
+	assert.Equal(t, SeverityNotice, SeverityNotice.clamp())
+	assert.Equal(t, SeverityFatal, Severity(42).clamp())
+}
+
+func TestSeverityErrorImplementsLeveled(t *testing.T) {
+	assert.Implements(t, (*Leveled)(nil), &severityError{})
+}
+
+func TestSeverityErrorError(t *testing.T) {
+	obj := &severityError{
+		msg: "some message",
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, "some message", result)
+}
+
+func TestSeverityErrorSeverity(t *testing.T) {
+	obj := &severityError{
+		sev: SeverityNotice,
+	}
+
+	result := obj.Severity()
+
+	assert.Equal(t, SeverityNotice, result)
+}
+
+func TestSeverityErrorUnwrap(t *testing.T) {
+	obj := &severityError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestMultiSeverityErrorImplementsLeveled(t *testing.T) {
+	assert.Implements(t, (*Leveled)(nil), &multiSeverityError{})
+}
+
+func TestMultiSeverityErrorError(t *testing.T) {
+	obj := &multiSeverityError{
+		msg: "some message",
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, "some message", result)
+}
+
+func TestMultiSeverityErrorSeverity(t *testing.T) {
+	obj := &multiSeverityError{
+		sev: SeverityNotice,
+	}
+
+	result := obj.Severity()
+
+	assert.Equal(t, SeverityNotice, result)
+}
+
+func TestMultiSeverityErrorUnwrap(t *testing.T) {
+	err := errors.New("other error") //nolint:goerr113
+	obj := &multiSeverityError{
+		errs: []error{assert.AnError, err},
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []error{assert.AnError, err}, result)
+}
+
+func TestNewSeverity(t *testing.T) {
+	obj := NewSeverity(SeverityInfo, "some message")
+
+	assert.Equal(t, &severityError{
+		sev: SeverityInfo,
+		msg: "some message",
+	}, obj)
+}
+
+func TestSeverityWrap(t *testing.T) {
+	obj := SeverityWrap(SeverityFatal, assert.AnError)
+
+	assert.Equal(t, &severityError{
+		sev: SeverityFatal,
+		msg: assert.AnError.Error(),
+		err: assert.AnError,
+	}, obj)
+}
+
+func TestSeverityf(t *testing.T) {
+	obj := Severityf(SeverityDebug, "this is a test %w", assert.AnError)
+
+	assert.Equal(t, &severityError{
+		sev: SeverityDebug,
+		msg: fmt.Sprintf("this is a test %s", assert.AnError),
+		err: assert.AnError,
+	}, obj)
+}
+
+func TestSeverityfMulti(t *testing.T) {
+	err := errors.New("other error") //nolint:goerr113
+
+	obj := Severityf(SeverityInfo, "this is a test %w and %w", assert.AnError, err)
+
+	assert.Equal(t, &multiSeverityError{
+		sev:  SeverityInfo,
+		msg:  fmt.Sprintf("this is a test %s and %s", assert.AnError, err),
+		errs: []error{assert.AnError, err},
+	}, obj)
+}
+
+func TestSeverityOfPlain(t *testing.T) {
+	result := SeverityOf(assert.AnError)
+
+	assert.Equal(t, SeverityError, result)
+}
+
+func TestSeverityOfNil(t *testing.T) {
+	result := SeverityOf(nil)
+
+	assert.Equal(t, SeverityError, result)
+}
+
+func TestSeverityOfWarning(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", NewWarning("test warning"))
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityWarning, result)
+}
+
+func TestSeverityOfLeveled(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", NewSeverity(SeverityNotice, "test notice"))
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityNotice, result)
+}
+
+func TestSeverityOfOutermost(t *testing.T) {
+	err := Severityf(SeverityInfo, "wrapped: %w", NewWarning("test warning"))
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityInfo, result)
+}
+
+func TestSeverityOfClamped(t *testing.T) {
+	err := NewSeverity(Severity(42), "test")
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityFatal, result)
+}
+
+func TestSeverityOfJoined(t *testing.T) {
+	err := errors.Join(assert.AnError, NewSeverity(SeverityNotice, "test notice"), NewWarning("test warning"))
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityError, result)
+}
+
+func TestSeverityOfJoinedHighest(t *testing.T) {
+	err := errors.Join(NewSeverity(SeverityNotice, "test notice"), NewWarning("test warning"), NewSeverity(SeverityInfo, "test info"))
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityWarning, result)
+}
+
+func TestSeverityOfJoinedNested(t *testing.T) {
+	err := fmt.Errorf("outer: %w", errors.Join(NewWarning("test warning"), errors.Join(NewSeverity(SeverityNotice, "test notice"), NewSeverity(SeverityFatal, "test fatal"))))
+
+	result := SeverityOf(err)
+
+	assert.Equal(t, SeverityFatal, result)
+}
+
+// emptyJoin is an error wrapping an empty list of errors.
+type emptyJoin struct{}
+
+func (emptyJoin) Error() string {
+	return "empty"
+}
+
+func (emptyJoin) Unwrap() []error {
+	return nil
+}
+
+func TestSeverityOfJoinedEmpty(t *testing.T) {
+	result := SeverityOf(emptyJoin{})
+
+	assert.Equal(t, SeverityError, result)
+}
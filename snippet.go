@@ -0,0 +1,381 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snippetTabWidth is the width of the tab stops used when expanding
+// tabs in source snippets.
+const snippetTabWidth = 4
+
+// Characters used to underline spans in source snippets.
+const (
+	snippetPrimary   = '^' // Underlines the position of the error
+	snippetSecondary = '-' // Underlines the position of a note
+)
+
+// wideRanges lists the ranges of runes that are displayed with double
+// width by terminals, such as CJK ideographs and emoji.
+var wideRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x115f, Stride: 1},
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1},
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1},
+		{Lo: 0x3400, Hi: 0x4dbf, Stride: 1},
+		{Lo: 0x4e00, Hi: 0x9fff, Stride: 1},
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1},
+		{Lo: 0xac00, Hi: 0xd7a3, Stride: 1},
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1},
+		{Lo: 0xfe30, Hi: 0xfe4f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1},
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x20000, Hi: 0x2fffd, Stride: 1},
+		{Lo: 0x30000, Hi: 0x3fffd, Stride: 1},
+	},
+}
+
+// runeWidth returns the number of terminal columns occupied by a
+// rune.  Combining marks and format characters occupy no columns,
+// and wide characters occupy two.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wideRanges, r):
+		return 2
+	}
+
+	return 1
+}
+
+// displayColumn returns the display column, starting at 0, of the
+// specified byte column, starting at 1, of a line of source text.
+// Tabs are expanded to the next tab stop.  Byte columns beyond the
+// end of the line are assumed to occupy one column each.
+func displayColumn(line string, col int) int {
+	width := 0
+	i := 0
+	for i < len(line) && i < col-1 {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if r == '\t' {
+			width += snippetTabWidth - width%snippetTabWidth
+		} else {
+			width += runeWidth(r)
+		}
+		i += size
+	}
+	if col-1 > i {
+		width += col - 1 - i
+	}
+
+	return width
+}
+
+// expandTabs expands the tabs in a line of source text to the next
+// tab stop, consistent with displayColumn.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+
+	buf := &strings.Builder{}
+	width := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := snippetTabWidth - width%snippetTabWidth
+			buf.WriteString(strings.Repeat(" ", n))
+			width += n
+		} else {
+			buf.WriteRune(r)
+			width += runeWidth(r)
+		}
+	}
+
+	return buf.String()
+}
+
+// snippetSpan describes a span of source text to be underlined in a
+// source snippet.
+type snippetSpan struct {
+	pos     Position // The position of the span
+	primary bool     // True if this is the position of the error
+	label   string   // Label for the span
+}
+
+// endLine returns the last line of the span.
+func (s snippetSpan) endLine() int {
+	if s.pos.End != nil && s.pos.End.Line > s.pos.Line {
+		return s.pos.End.Line
+	}
+
+	return s.pos.Line
+}
+
+// columns returns the range of byte columns of the specified line of
+// source text covered by the span, as a half-open range starting at
+// 1.  Columns beyond the end of the line are clamped to the end of
+// the line.  If the span does not cover the line, both columns are 0.
+func (s snippetSpan) columns(lineNo int, line string) (int, int) {
+	if lineNo < s.pos.Line || lineNo > s.endLine() {
+		return 0, 0
+	}
+	text := strings.TrimRight(line, " \t")
+	indent := len(text) - len(strings.TrimLeft(text, " \t")) + 1
+
+	// Determine the start of the range
+	start := indent
+	if lineNo == s.pos.Line && s.pos.Column > 0 {
+		start = s.pos.Column
+	}
+	if start > len(text)+1 {
+		start = len(text) + 1
+	}
+
+	// Determine the end of the range
+	end := len(text) + 1
+	switch {
+	case s.pos.End != nil && s.pos.End.IsValid() && lineNo == s.pos.End.Line && s.pos.End.Column > 0:
+		end = s.pos.End.Column
+
+	case lineNo == s.endLine() && s.endLine() == s.pos.Line && s.pos.Column > 0:
+		// Underline the character at the position
+		end = start + 1
+		if start <= len(line) {
+			_, size := utf8.DecodeRuneInString(line[start-1:])
+			end = start + size
+		}
+	}
+	if end > len(text)+1 {
+		end = len(text) + 1
+	}
+	if end <= start {
+		end = start + 1
+	}
+
+	return start, end
+}
+
+// snippetRow is a helper that renders the underlines and labels of
+// the spans covering a single line of source text.  It returns the
+// rows to emit beneath the line, without the gutter.
+func snippetRow(lineNo int, line string, spans []snippetSpan) []string {
+	type mark struct {
+		from, to int    // Display columns
+		label    string // Label to emit
+	}
+
+	// Compute the underline
+	underline := []rune{}
+	labels := []mark{}
+	for _, pass := range []bool{false, true} {
+		for _, span := range spans {
+			if span.primary != pass {
+				continue
+			}
+			start, end := span.columns(lineNo, line)
+			if start == 0 {
+				continue
+			}
+
+			from, to := displayColumn(line, start), displayColumn(line, end)
+			if to <= from {
+				to = from + 1
+			}
+			for len(underline) < to {
+				underline = append(underline, ' ')
+			}
+			ch := snippetSecondary
+			if span.primary {
+				ch = snippetPrimary
+			}
+			for i := from; i < to; i++ {
+				underline[i] = ch
+			}
+
+			if span.label != "" && lineNo == span.endLine() {
+				labels = append(labels, mark{from: from, to: to, label: span.label})
+			}
+		}
+	}
+	if len(underline) == 0 {
+		return nil
+	}
+
+	// Attach the labels; the label of the rightmost span goes on the
+	// underline itself, and the others go on subsequent rows
+	rows := []string{string(underline)}
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].from > labels[j].from
+	})
+	for i, m := range labels {
+		if i == 0 && m.to >= len(underline) {
+			rows[0] += " " + m.label
+		} else {
+			rows = append(rows, strings.Repeat(" ", m.from)+m.label)
+		}
+	}
+
+	return rows
+}
+
+// renderSnippet renders a source snippet showing the lines of source
+// text covered by the specified spans, with a gutter of line numbers
+// and the spans underlined.  Each row of the snippet is preceded by a
+// newline.  All spans must have valid positions within the lines of
+// source text.
+func renderSnippet(lines []string, spans []snippetSpan) string {
+	// Select the lines to show
+	lineSet := map[int]bool{}
+	for _, span := range spans {
+		for i := span.pos.Line; i <= span.endLine(); i++ {
+			lineSet[i] = true
+		}
+	}
+	show := make([]int, 0, len(lineSet))
+	for lineNo := range lineSet {
+		show = append(show, lineNo)
+	}
+	sort.Ints(show)
+
+	// Render the snippet
+	width := len(strconv.Itoa(show[len(show)-1]))
+	blank := strings.Repeat(" ", width) + " |"
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "\n%s", blank)
+	for i, lineNo := range show {
+		if i > 0 && lineNo > show[i-1]+1 {
+			buf.WriteString("\n...")
+		}
+		line := lines[lineNo-1]
+		fmt.Fprintf(buf, "\n%s", strings.TrimRight(fmt.Sprintf("%*d | %s", width, lineNo, expandTabs(line)), " "))
+		for _, row := range snippetRow(lineNo, line, spans) {
+			fmt.Fprintf(buf, "\n%s %s", blank, strings.TrimRight(row, " "))
+		}
+	}
+
+	return buf.String()
+}
+
+// snippetRenderer renders source snippets for errors, caching the
+// source text obtained from a SourceProvider.
+type snippetRenderer struct {
+	sync.Mutex
+
+	src   SourceProvider      // The source of the source text
+	cache map[string][]string // Cache of the lines of source text
+}
+
+// lines returns the lines of the named file, or nil if the source is
+// not available.
+func (sr *snippetRenderer) lines(file string) []string {
+	// Lock the mutex for thread safety
+	sr.Lock()
+	defer sr.Unlock()
+
+	if lines, ok := sr.cache[file]; ok {
+		return lines
+	}
+
+	var lines []string
+	if data, err := sr.src.Source(file); err == nil {
+		lines = strings.Split(string(data), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSuffix(line, "\r")
+		}
+	}
+	if sr.cache == nil {
+		sr.cache = map[string][]string{}
+	}
+	sr.cache[file] = lines
+
+	return lines
+}
+
+// render renders a source snippet for an error, if it has a position
+// and its source text is available.  Notes with positions in the same
+// file are rendered as labeled secondary spans; the notes that could
+// not be rendered are returned.  If no snippet can be rendered, an
+// empty string and all the notes are returned.
+func (sr *snippetRenderer) render(err error, notes []Note) (string, []Note) {
+	pos, ok := PositionOf(err)
+	if !ok || !pos.IsValid() {
+		return "", notes
+	}
+	lines := sr.lines(pos.File)
+	if pos.Line > len(lines) {
+		return "", notes
+	}
+
+	// Collect the spans
+	spans := []snippetSpan{{pos: clampEnd(pos, len(lines)), primary: true}}
+	remaining := []Note{}
+	for _, note := range notes {
+		if note.Position.File != pos.File || !note.Position.IsValid() || note.Position.Line > len(lines) {
+			remaining = append(remaining, note)
+			continue
+		}
+		spans = append(spans, snippetSpan{
+			pos:   clampEnd(note.Position, len(lines)),
+			label: note.Message,
+		})
+	}
+
+	return renderSnippet(lines, spans), remaining
+}
+
+// clampEnd is a helper that ensures that the end of a position does
+// not extend beyond the last line of the source text.
+func clampEnd(pos Position, numLines int) Position {
+	if pos.End != nil && pos.End.Line > numLines {
+		end := *pos.End
+		end.Line = numLines
+		end.Column = 0
+		pos.End = &end
+	}
+
+	return pos
+}
+
+// FormatSnippets specifies that the formatted error should include a
+// snippet of the source text at the position of the error, in the
+// style of the Rust compiler: the offending lines are shown with a
+// gutter of line numbers, and the range of the error is underlined
+// with carets.  Notes with positions in the same file are shown as
+// labeled secondary spans underlined with dashes, rather than on
+// separate lines.  The source text is obtained from the specified
+// SourceProvider; tabs are expanded and multi-byte characters are
+// handled when aligning the underlines.  If the error has no
+// position, or its source text is not available, no snippet is
+// included.  Unlike FormatCodes, this applies to all formats,
+// including those set by other options, such as FormatError.
+func FormatSnippets(src SourceProvider) FormatOption {
+	return func(f *Formatters) {
+		f.snippets = &snippetRenderer{
+			src: src,
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSource struct {
+	SourceMap
+
+	calls int
+}
+
+func (cs *countingSource) Source(file string) ([]byte, error) {
+	cs.calls++
+	return cs.SourceMap.Source(file)
+}
+
+func TestRuneWidth(t *testing.T) {
+	assert.Equal(t, 1, runeWidth('a'))
+	assert.Equal(t, 1, runeWidth('é'))
+	assert.Equal(t, 0, runeWidth('́'))
+	assert.Equal(t, 0, runeWidth('​'))
+	assert.Equal(t, 2, runeWidth('日'))
+	assert.Equal(t, 2, runeWidth('한'))
+	assert.Equal(t, 2, runeWidth('😀'))
+}
+
+func TestDisplayColumnASCII(t *testing.T) {
+	assert.Equal(t, 0, displayColumn("key = 1", 1))
+	assert.Equal(t, 4, displayColumn("key = 1", 5))
+}
+
+func TestDisplayColumnTabs(t *testing.T) {
+	assert.Equal(t, 4, displayColumn("\tkey", 2))
+	assert.Equal(t, 8, displayColumn("ab\tc\tkey", 6))
+}
+
+func TestDisplayColumnMultiByte(t *testing.T) {
+	assert.Equal(t, 2, displayColumn("é = 1", 4))
+	assert.Equal(t, 5, displayColumn("日本 = 1", 8))
+}
+
+func TestDisplayColumnPastEnd(t *testing.T) {
+	assert.Equal(t, 5, displayColumn("abc", 6))
+}
+
+func TestExpandTabsNone(t *testing.T) {
+	assert.Equal(t, "key = 1", expandTabs("key = 1"))
+}
+
+func TestExpandTabs(t *testing.T) {
+	assert.Equal(t, "    key =   1", expandTabs("\tkey =\t1"))
+	assert.Equal(t, "日本    x", expandTabs("日本\tx"))
+}
+
+func TestSnippetSpanEndLine(t *testing.T) {
+	assert.Equal(t, 3, snippetSpan{pos: Position{Line: 3}}.endLine())
+	assert.Equal(t, 3, snippetSpan{pos: Position{Line: 3, End: &Position{Line: 2}}}.endLine())
+	assert.Equal(t, 5, snippetSpan{pos: Position{Line: 3, End: &Position{Line: 5}}}.endLine())
+}
+
+func TestSnippetSpanColumnsOutside(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 2}}
+
+	start, end := obj.columns(2, "key = 1")
+
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 0, end)
+}
+
+func TestSnippetSpanColumnsCharacter(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 4}}
+
+	start, end := obj.columns(3, "日本")
+
+	assert.Equal(t, 4, start)
+	assert.Equal(t, 7, end)
+}
+
+func TestSnippetSpanColumnsPastEnd(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 9}}
+
+	start, end := obj.columns(3, "key")
+
+	assert.Equal(t, 4, start)
+	assert.Equal(t, 5, end)
+}
+
+func TestSnippetSpanColumnsRangePastEnd(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 7, End: &Position{Line: 3, Column: 42}}}
+
+	start, end := obj.columns(3, "key = 123  ")
+
+	assert.Equal(t, 7, start)
+	assert.Equal(t, 10, end)
+}
+
+func TestSnippetSpanColumnsWholeLine(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3}}
+
+	start, end := obj.columns(3, "  key = 1  ")
+
+	assert.Equal(t, 3, start)
+	assert.Equal(t, 10, end)
+}
+
+func TestSnippetSpanColumnsRange(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 7, End: &Position{Line: 3, Column: 10}}}
+
+	start, end := obj.columns(3, "key = 123")
+
+	assert.Equal(t, 7, start)
+	assert.Equal(t, 10, end)
+}
+
+func TestSnippetSpanColumnsEmptyRange(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 7, End: &Position{Line: 3, Column: 7}}}
+
+	start, end := obj.columns(3, "key = 123")
+
+	assert.Equal(t, 7, start)
+	assert.Equal(t, 8, end)
+}
+
+func TestSnippetSpanColumnsMultiLine(t *testing.T) {
+	obj := snippetSpan{pos: Position{Line: 3, Column: 7, End: &Position{Line: 5, Column: 3}}}
+
+	start3, end3 := obj.columns(3, "key = [")
+	start4, end4 := obj.columns(4, "  1, 2,")
+	start5, end5 := obj.columns(5, "]")
+
+	assert.Equal(t, []int{7, 8}, []int{start3, end3})
+	assert.Equal(t, []int{3, 8}, []int{start4, end4})
+	assert.Equal(t, []int{1, 2}, []int{start5, end5})
+}
+
+func TestSnippetRowNone(t *testing.T) {
+	result := snippetRow(2, "key = 1", []snippetSpan{{pos: Position{Line: 3}}})
+
+	assert.Nil(t, result)
+}
+
+func TestSnippetRowPrimary(t *testing.T) {
+	result := snippetRow(3, "\tkey = 1", []snippetSpan{
+		{pos: Position{Line: 3, Column: 2, End: &Position{Line: 3, Column: 5}}, primary: true},
+	})
+
+	assert.Equal(t, []string{"    ^^^"}, result)
+}
+
+func TestSnippetRowOverlap(t *testing.T) {
+	result := snippetRow(3, "key = 123", []snippetSpan{
+		{pos: Position{Line: 3, Column: 7, End: &Position{Line: 3, Column: 8}}, primary: true},
+		{pos: Position{Line: 3, Column: 1, End: &Position{Line: 3, Column: 10}}, label: "the setting"},
+	})
+
+	assert.Equal(t, []string{"------^-- the setting"}, result)
+}
+
+func TestSnippetRowLabels(t *testing.T) {
+	result := snippetRow(3, "key = 123", []snippetSpan{
+		{pos: Position{Line: 3, Column: 1, End: &Position{Line: 3, Column: 4}}, label: "the key"},
+		{pos: Position{Line: 3, Column: 7, End: &Position{Line: 3, Column: 10}}, label: "the value"},
+		{pos: Position{Line: 3, Column: 5}, primary: true},
+	})
+
+	assert.Equal(t, []string{"--- ^ --- the value", "the key"}, result)
+}
+
+func TestSnippetRowLabelNotRightmost(t *testing.T) {
+	result := snippetRow(3, "key = 123", []snippetSpan{
+		{pos: Position{Line: 3, Column: 1, End: &Position{Line: 3, Column: 4}}, label: "the key"},
+		{pos: Position{Line: 3, Column: 7, End: &Position{Line: 3, Column: 10}}, primary: true},
+	})
+
+	assert.Equal(t, []string{"---   ^^^", "the key"}, result)
+}
+
+func TestSnippetRowMultiLineLabel(t *testing.T) {
+	span := snippetSpan{pos: Position{Line: 3, Column: 7, End: &Position{Line: 4, Column: 2}}, label: "the list"}
+
+	result3 := snippetRow(3, "key = [", []snippetSpan{span})
+	result4 := snippetRow(4, "]", []snippetSpan{span})
+
+	assert.Equal(t, []string{"      -"}, result3)
+	assert.Equal(t, []string{"- the list"}, result4)
+}
+
+func TestRenderSnippet(t *testing.T) {
+	lines := []string{"a = 1", "b = 2", "c = 3", "", "", "", "", "", "", "a = 4"}
+
+	result := renderSnippet(lines, []snippetSpan{
+		{pos: Position{Line: 10, Column: 1}, primary: true},
+		{pos: Position{Line: 1, Column: 1}, label: "first defined here"},
+		{pos: Position{Line: 2, Column: 1}},
+	})
+
+	assert.Equal(t, "\n   |\n 1 | a = 1\n   | - first defined here\n 2 | b = 2\n   | -\n...\n10 | a = 4\n   | ^", result)
+}
+
+func TestRenderSnippetColumnPastEnd(t *testing.T) {
+	lines := []string{"[server]", "port = 8080 "}
+
+	result := renderSnippet(lines, []snippetSpan{
+		{pos: Position{Line: 2, Column: 100}, primary: true},
+	})
+
+	assert.Equal(t, "\n  |\n2 | port = 8080\n  |            ^", result)
+}
+
+func TestSnippetRendererLinesCached(t *testing.T) {
+	src := &countingSource{SourceMap: SourceMap{"file.cfg": []byte("a = 1\r\nb = 2\n")}}
+	obj := &snippetRenderer{src: src}
+
+	result1 := obj.lines("file.cfg")
+	result2 := obj.lines("file.cfg")
+
+	assert.Equal(t, []string{"a = 1", "b = 2", ""}, result1)
+	assert.Equal(t, result1, result2)
+	assert.Equal(t, 1, src.calls)
+}
+
+func TestSnippetRendererLinesUnavailable(t *testing.T) {
+	src := &countingSource{SourceMap: SourceMap{}}
+	obj := &snippetRenderer{src: src}
+
+	result1 := obj.lines("file.cfg")
+	result2 := obj.lines("file.cfg")
+
+	assert.Nil(t, result1)
+	assert.Nil(t, result2)
+	assert.Equal(t, 1, src.calls)
+}
+
+func TestSnippetRendererRenderNoPosition(t *testing.T) {
+	notes := []Note{Notef("a note")}
+	obj := &snippetRenderer{src: SourceMap{}}
+
+	result, remaining := obj.render(assert.AnError, notes)
+
+	assert.Equal(t, "", result)
+	assert.Equal(t, notes, remaining)
+}
+
+func TestSnippetRendererRenderUnavailable(t *testing.T) {
+	notes := []Note{Notef("a note")}
+	obj := &snippetRenderer{src: SourceMap{}}
+
+	result, remaining := obj.render(ErrorAt(Position{File: "file.cfg", Line: 1}, "test error"), notes)
+
+	assert.Equal(t, "", result)
+	assert.Equal(t, notes, remaining)
+}
+
+func TestSnippetRendererRenderPastEnd(t *testing.T) {
+	obj := &snippetRenderer{src: SourceMap{"file.cfg": []byte("a = 1")}}
+
+	result, remaining := obj.render(ErrorAt(Position{File: "file.cfg", Line: 2}, "test error"), nil)
+
+	assert.Equal(t, "", result)
+	assert.Nil(t, remaining)
+}
+
+func TestSnippetRendererRender(t *testing.T) {
+	obj := &snippetRenderer{src: SourceMap{"file.cfg": []byte("a = 1\na = 2\n")}}
+	notes := []Note{
+		NoteAt(Position{File: "file.cfg", Line: 1, Column: 1}, "first defined here"),
+		NoteAt(Position{File: "other.cfg", Line: 1}, "other file"),
+		NoteAt(Position{File: "file.cfg", Line: 9}, "past end"),
+		Notef("no position"),
+	}
+
+	result, remaining := obj.render(ErrorAt(Position{File: "file.cfg", Line: 2, Column: 1, End: &Position{Line: 9}}, "test error"), notes)
+
+	assert.Equal(t, "\n  |\n1 | a = 1\n  | - first defined here\n2 | a = 2\n  | ^^^^^\n3 |\n  | ^", result)
+	assert.Equal(t, notes[1:], remaining)
+}
+
+func TestClampEnd(t *testing.T) {
+	assert.Equal(t, Position{Line: 2}, clampEnd(Position{Line: 2}, 3))
+	assert.Equal(t, Position{Line: 2, End: &Position{Line: 3, Column: 4}}, clampEnd(Position{Line: 2, End: &Position{Line: 3, Column: 4}}, 3))
+	assert.Equal(t, Position{Line: 2, End: &Position{Line: 3}}, clampEnd(Position{Line: 2, End: &Position{Line: 5, Column: 4}}, 3))
+}
+
+func TestFormatSnippets(t *testing.T) {
+	src := SourceMap{}
+	obj := &Formatters{}
+
+	opt := FormatSnippets(src)
+	opt(obj)
+
+	assert.Equal(t, &snippetRenderer{src: src}, obj.snippets)
+}
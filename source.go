@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SourceProvider describes a source of the text of the files that
+// positions refer to, for use in rendering source snippets; see
+// FormatSnippets.
+type SourceProvider interface {
+	// Source returns the contents of the named file.
+	Source(file string) ([]byte, error)
+}
+
+// SourceMap is an implementation of SourceProvider that maps file
+// names to their contents.  It is useful when the source text is
+// already in memory, or is not stored in files at all.
+type SourceMap map[string][]byte
+
+// Source returns the contents of the named file.  If the file is not
+// in the map, an error wrapping fs.ErrNotExist is returned.
+func (sm SourceMap) Source(file string) ([]byte, error) {
+	data, ok := sm[file]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: file, Err: fs.ErrNotExist}
+	}
+
+	return data, nil
+}
+
+// fsSource is an implementation of SourceProvider that reads files
+// from an fs.FS.
+type fsSource struct {
+	fsys fs.FS // The file system to read from
+}
+
+// SourceFS constructs a SourceProvider that reads files from the
+// specified file system.  File names are interpreted as for
+// fs.ReadFile, so they must be slash-separated and unrooted; use
+// SourceDir for file names that may be absolute.
+func SourceFS(fsys fs.FS) SourceProvider {
+	return &fsSource{
+		fsys: fsys,
+	}
+}
+
+// Source returns the contents of the named file.
+func (fss *fsSource) Source(file string) ([]byte, error) {
+	return fs.ReadFile(fss.fsys, file)
+}
+
+// SourceDir is an implementation of SourceProvider that reads files
+// from the operating system's file system.  Relative file names are
+// interpreted relative to the named directory; if the directory is
+// empty, they are relative to the current directory.
+type SourceDir string
+
+// Source returns the contents of the named file.
+func (sd SourceDir) Source(file string) ([]byte, error) {
+	path := filepath.FromSlash(file)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(string(sd), path)
+	}
+
+	return os.ReadFile(path)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceMapImplementsSourceProvider(t *testing.T) {
+	assert.Implements(t, (*SourceProvider)(nil), SourceMap{})
+}
+
+func TestSourceMapSourceFound(t *testing.T) {
+	obj := SourceMap{"file.cfg": []byte("contents")}
+
+	result, err := obj.Source("file.cfg")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("contents"), result)
+}
+
+func TestSourceMapSourceMissing(t *testing.T) {
+	obj := SourceMap{}
+
+	result, err := obj.Source("file.cfg")
+
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Nil(t, result)
+}
+
+func TestSourceFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+
+	result := SourceFS(fsys)
+
+	assert.Equal(t, &fsSource{fsys: fsys}, result)
+}
+
+func TestFSSourceSource(t *testing.T) {
+	obj := &fsSource{
+		fsys: fstest.MapFS{
+			"dir/file.cfg": &fstest.MapFile{Data: []byte("contents")},
+		},
+	}
+
+	result, err := obj.Source("dir/file.cfg")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("contents"), result)
+}
+
+func TestSourceDirImplementsSourceProvider(t *testing.T) {
+	assert.Implements(t, (*SourceProvider)(nil), SourceDir(""))
+}
+
+func TestSourceDirSourceRelative(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.cfg"), []byte("contents"), 0o600))
+	obj := SourceDir(dir)
+
+	result, err := obj.Source("file.cfg")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("contents"), result)
+}
+
+func TestSourceDirSourceAbsolute(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.cfg")
+	require.NoError(t, os.WriteFile(path, []byte("contents"), 0o600))
+	obj := SourceDir("/nonexistent")
+
+	result, err := obj.Source(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("contents"), result)
+}
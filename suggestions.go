@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+// TextEdit describes a single edit to a file.  The bytes in the
+// half-open range [Start, End) are replaced with NewText; if Start
+// and End are equal, NewText is inserted at that offset.
+type TextEdit struct {
+	File    string // The name of the file to edit
+	Start   int    // Byte offset of the start of the range
+	End     int    // Byte offset of the end of the range
+	NewText string // The replacement text
+}
+
+// Suggestion describes a machine-applicable fix for a diagnostic.  A
+// suggestion consists of a description and a list of edits, which
+// are applied together or not at all.
+type Suggestion struct {
+	Description string     // Description of the suggested fix
+	Edits       []TextEdit // The edits making up the fix
+}
+
+// Suggester is a utility interface for errors that carry suggested
+// fixes.  The Suggestions helper collects the suggestions from all
+// errors in an error chain that implement Suggester.
+type Suggester interface {
+	error
+
+	// Suggestions returns the suggestions attached to the error.
+	Suggestions() []Suggestion
+}
+
+// suggestedError is an implementation of Suggester that wraps another
+// error.
+type suggestedError struct {
+	sugs []Suggestion // The suggested fixes
+	err  error        // The wrapped error
+}
+
+// Error returns the error message.
+func (se *suggestedError) Error() string {
+	return se.err.Error()
+}
+
+// Suggestions returns the suggestions attached to the error.
+func (se *suggestedError) Suggestions() []Suggestion {
+	return se.sugs
+}
+
+// Unwrap returns the wrapped error.
+func (se *suggestedError) Unwrap() error {
+	return se.err
+}
+
+// WithSuggestions attaches one or more suggested fixes to an error.
+// The error message is not altered.
+func WithSuggestions(err error, sugs ...Suggestion) error {
+	return &suggestedError{
+		sugs: sugs,
+		err:  err,
+	}
+}
+
+// Suggestions retrieves all the suggested fixes associated with an
+// error.  Like Notes, it follows the entire error chain and collects
+// the suggestions from every error implementing Suggester, with the
+// suggestions from outer errors preceding those from inner errors.
+func Suggestions(err error) []Suggestion {
+	var sugs []Suggestion
+	walk(err, func(e error) bool {
+		if se, ok := e.(Suggester); ok {
+			sugs = append(sugs, se.Suggestions()...)
+		}
+
+		return false
+	})
+
+	return sugs
+}
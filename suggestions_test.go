@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestedErrorImplementsSuggester(t *testing.T) {
+	assert.Implements(t, (*Suggester)(nil), &suggestedError{})
+}
+
+func TestSuggestedErrorError(t *testing.T) {
+	obj := &suggestedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, assert.AnError.Error(), result)
+}
+
+func TestSuggestedErrorSuggestions(t *testing.T) {
+	obj := &suggestedError{
+		sugs: []Suggestion{{Description: "fix"}},
+	}
+
+	result := obj.Suggestions()
+
+	assert.Equal(t, []Suggestion{{Description: "fix"}}, result)
+}
+
+func TestSuggestedErrorUnwrap(t *testing.T) {
+	obj := &suggestedError{
+		err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+func TestWithSuggestions(t *testing.T) {
+	result := WithSuggestions(assert.AnError, Suggestion{Description: "fix 1"}, Suggestion{Description: "fix 2"})
+
+	assert.Equal(t, &suggestedError{
+		sugs: []Suggestion{{Description: "fix 1"}, {Description: "fix 2"}},
+		err:  assert.AnError,
+	}, result)
+}
+
+func TestSuggestionsChain(t *testing.T) {
+	err := WithSuggestions(
+		fmt.Errorf("wrapped: %w", WithSuggestions(assert.AnError, Suggestion{Description: "inner"})),
+		Suggestion{Description: "outer"},
+	)
+
+	result := Suggestions(err)
+
+	assert.Equal(t, []Suggestion{{Description: "outer"}, {Description: "inner"}}, result)
+}
+
+func TestSuggestionsMissing(t *testing.T) {
+	result := Suggestions(assert.AnError)
+
+	assert.Nil(t, result)
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// defaultSummaryTemplate is the template used by SummaryReporter if
+// none is specified with SummaryTemplate.
+const defaultSummaryTemplate = `{{phrase .Errors .Warnings}} generated
+{{- range .Breakdown}}
+    {{.Key}}: {{phrase .Errors .Warnings}}{{end}}`
+
+// summaryFuncs contains the helper functions available to templates
+// specified with SummaryTemplate, in addition to those available to
+// templates specified with FormatTemplate.
+var summaryFuncs = template.FuncMap{
+	"plural": plural,
+	"phrase": summaryPhrase,
+}
+
+// defaultSummary is the parsed default summary template.
+var defaultSummary = template.Must(template.New("summary").Funcs(templateFuncs).Funcs(summaryFuncs).Parse(defaultSummaryTemplate))
+
+// plural formats a count with the singular or plural form of a noun,
+// as appropriate, e.g., "1 error" or "3 errors".
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
+
+// summaryPhrase formats counts of errors and warnings, e.g., "3
+// errors and 1 warning" or "no errors or warnings".
+func summaryPhrase(errors, warnings int) string {
+	switch {
+	case errors > 0 && warnings > 0:
+		return plural(errors, "error", "errors") + " and " + plural(warnings, "warning", "warnings")
+	case errors > 0:
+		return plural(errors, "error", "errors")
+	case warnings > 0:
+		return plural(warnings, "warning", "warnings")
+	}
+
+	return "no errors or warnings"
+}
+
+// summaryCounts contains counts of reported errors by severity.
+type summaryCounts [numSeverities]int
+
+// errors returns the number of errors, including fatal errors.
+func (sc *summaryCounts) errors() int {
+	return sc[SeverityError] + sc[SeverityFatal]
+}
+
+// SummaryEntry describes the counts for one key of a breakdown in a
+// SummaryView.
+type SummaryEntry struct {
+	Key      string // The key, such as a code or file name
+	Errors   int    // Number of errors, including fatal errors
+	Warnings int    // Number of warnings
+	Total    int    // Number of errors of any severity
+}
+
+// SummaryView is the view of the reported errors that is passed to
+// templates specified with SummaryTemplate.
+type SummaryView struct {
+	Errors    int              // Number of errors, including fatal errors
+	Warnings  int              // Number of warnings
+	Total     int              // Number of errors of any severity
+	Counts    map[Severity]int // Number of errors by severity
+	Breakdown []SummaryEntry   // Counts by key, sorted by key
+}
+
+// SummaryReporter is a Reporter that counts the errors and warnings
+// reported using it, and writes a summary, such as "3 errors and 2
+// warnings generated", to a specified io.Writer stream.
+type SummaryReporter struct {
+	sync.Mutex
+
+	out       io.Writer                 // The output stream to write to
+	tmpl      *template.Template        // Template for the summary
+	breakdown func(error) string        // Function for the breakdown key
+	always    bool                      // Summarize even if nothing reported
+	counts    summaryCounts             // Counts by severity
+	keys      map[string]*summaryCounts // Counts by breakdown key
+	done      bool                      // Set once the summary is written
+	rep       Reporter                  // Child reporter
+}
+
+// SummaryReporterOption describes an option for a SummaryReporter.
+type SummaryReporterOption func(*SummaryReporter)
+
+// SummaryBreakdown specifies a function that determines a key, such
+// as a diagnostic code, for each reported error.  The summary is then
+// followed by the counts for each key, sorted by key.  Errors for
+// which the function returns an empty string are not included in the
+// breakdown, nor are keys with no errors or warnings, unless the
+// SummaryAlways option is passed.
+func SummaryBreakdown(key func(err error) string) SummaryReporterOption {
+	return func(sr *SummaryReporter) {
+		sr.breakdown = key
+	}
+}
+
+// SummaryByCode specifies that the summary should be broken down by
+// the diagnostic code of the reported errors (see CodeOf).
+func SummaryByCode() SummaryReporterOption {
+	return SummaryBreakdown(func(err error) string {
+		code, _ := CodeOf(err)
+		return code
+	})
+}
+
+// SummaryByFile specifies that the summary should be broken down by
+// the file of the position of the reported errors (see PositionOf).
+func SummaryByFile() SummaryReporterOption {
+	return SummaryBreakdown(func(err error) string {
+		pos, _ := PositionOf(err)
+		return pos.File
+	})
+}
+
+// SummaryAlways specifies that the summary should be written even if
+// no errors or warnings were reported, in which case the default
+// summary is "no errors or warnings generated".
+func SummaryAlways() SummaryReporterOption {
+	return func(sr *SummaryReporter) {
+		sr.always = true
+	}
+}
+
+// SummaryTemplate specifies a text/template template for the summary.
+// The template is executed with a *SummaryView describing the counts
+// of the reported errors, and may use the helper functions available
+// to templates specified with FormatTemplate, as well as the
+// following:
+//
+//	plural N SINGULAR PLURAL   Format a count, e.g., "1 error"
+//	phrase ERRORS WARNINGS     Format counts, e.g., "1 error and 2 warnings"
+//
+// The default template is:
+//
+//	{{phrase .Errors .Warnings}} generated
+//	{{- range .Breakdown}}
+//	    {{.Key}}: {{phrase .Errors .Warnings}}{{end}}
+//
+// Trailing newlines are removed from the output, and a single newline
+// is added.  If the template cannot be parsed, the returned option
+// does nothing, leaving the template set by earlier options in place;
+// templates from configuration files or other external sources
+// should be parsed with ParseSummaryTemplate instead, so that the
+// error can be reported.
+func SummaryTemplate(text string) SummaryReporterOption {
+	opt, err := ParseSummaryTemplate(text)
+	if err != nil {
+		return func(sr *SummaryReporter) {}
+	}
+
+	return opt
+}
+
+// ParseSummaryTemplate is similar to SummaryTemplate, except that an
+// error is returned if the template cannot be parsed.  This should be
+// preferred when the template comes from an external source, such as
+// a configuration file.
+func ParseSummaryTemplate(text string) (SummaryReporterOption, error) {
+	tmpl, err := template.New("summary").Funcs(templateFuncs).Funcs(summaryFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(sr *SummaryReporter) {
+		sr.tmpl = tmpl
+	}, nil
+}
+
+// NewSummaryReporter constructs a new summary reporter.  A summary
+// reporter counts the reported errors and warnings, and writes a
+// summary of the counts to the specified output stream when its
+// Summarize method is called, or when its Close method is called,
+// such as by the Close helper, if Summarize has not been called.  If
+// no errors or warnings were reported, no summary is written, even if
+// errors of lesser severity were reported, unless the SummaryAlways
+// option is passed.
+func NewSummaryReporter(out io.Writer, rep Reporter, options ...SummaryReporterOption) *SummaryReporter {
+	obj := &SummaryReporter{
+		out:  out,
+		tmpl: defaultSummary,
+		keys: map[string]*summaryCounts{},
+		rep:  rep,
+	}
+
+	// Apply options
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// Report is the core method of the Reporter interface.  It reports
+// the error being reported, using whatever method the Reporter
+// implementation uses, and passes on the error to the wrapped
+// Reporter.
+func (sr *SummaryReporter) Report(err error) {
+	sev := SeverityOf(err)
+	key := ""
+	if sr.breakdown != nil {
+		key = sr.breakdown(err)
+	}
+
+	sr.Lock()
+	sr.counts[sev]++
+	if key != "" {
+		if sr.keys[key] == nil {
+			sr.keys[key] = &summaryCounts{}
+		}
+		sr.keys[key][sev]++
+	}
+	sr.Unlock()
+
+	sr.rep.Report(err)
+}
+
+// Unwrap returns the Reporter or Reporters being wrapped by this
+// Reporter, returning a possibly empty list of Reporters.
+func (sr *SummaryReporter) Unwrap() []Reporter {
+	return []Reporter{sr.rep}
+}
+
+// view is a helper that constructs the SummaryView describing the
+// counts.  It must be called with the mutex held.
+func (sr *SummaryReporter) view() *SummaryView {
+	obj := &SummaryView{
+		Errors:   sr.counts.errors(),
+		Warnings: sr.counts[SeverityWarning],
+		Counts:   map[Severity]int{},
+	}
+	for sev, count := range sr.counts {
+		obj.Counts[Severity(sev)] = count
+		obj.Total += count
+	}
+
+	// Construct the breakdown
+	keys := make([]string, 0, len(sr.keys))
+	for key := range sr.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		counts := sr.keys[key]
+		entry := SummaryEntry{
+			Key:      key,
+			Errors:   counts.errors(),
+			Warnings: counts[SeverityWarning],
+		}
+		if entry.Errors+entry.Warnings == 0 && !sr.always {
+			continue
+		}
+		for _, count := range counts {
+			entry.Total += count
+		}
+		obj.Breakdown = append(obj.Breakdown, entry)
+	}
+
+	return obj
+}
+
+// Summarize writes the summary of the errors reported so far to the
+// output stream.  If no errors or warnings were reported, nothing is
+// written, unless the SummaryAlways option was passed.
+func (sr *SummaryReporter) Summarize() error {
+	// Lock the mutex for thread safety
+	sr.Lock()
+	defer sr.Unlock()
+
+	return sr.summarize()
+}
+
+// summarize is a helper that writes the summary.  It must be called
+// with the mutex held.
+func (sr *SummaryReporter) summarize() error {
+	sr.done = true
+	view := sr.view()
+	if view.Errors+view.Warnings == 0 && !sr.always {
+		return nil
+	}
+
+	buf := &strings.Builder{}
+	if err := sr.tmpl.Execute(buf, view); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(sr.out, strings.TrimRight(buf.String(), "\n"))
+
+	return err
+}
+
+// Close writes the summary to the output stream, if Summarize has not
+// already been called.
+func (sr *SummaryReporter) Close() error {
+	// Lock the mutex for thread safety
+	sr.Lock()
+	defer sr.Unlock()
+
+	if sr.done {
+		return nil
+	}
+
+	return sr.summarize()
+}
@@ -0,0 +1,399 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlural(t *testing.T) {
+	assert.Equal(t, "0 errors", plural(0, "error", "errors"))
+	assert.Equal(t, "1 error", plural(1, "error", "errors"))
+	assert.Equal(t, "3 errors", plural(3, "error", "errors"))
+}
+
+func TestSummaryPhrase(t *testing.T) {
+	assert.Equal(t, "3 errors and 1 warning", summaryPhrase(3, 1))
+	assert.Equal(t, "1 error", summaryPhrase(1, 0))
+	assert.Equal(t, "2 warnings", summaryPhrase(0, 2))
+	assert.Equal(t, "no errors or warnings", summaryPhrase(0, 0))
+}
+
+func TestSummaryCountsErrors(t *testing.T) {
+	obj := summaryCounts{}
+	obj[SeverityError] = 2
+	obj[SeverityFatal] = 1
+	obj[SeverityWarning] = 5
+
+	result := obj.errors()
+
+	assert.Equal(t, 3, result)
+}
+
+func TestSummaryReporterImplementsReporter(t *testing.T) {
+	assert.Implements(t, (*Reporter)(nil), &SummaryReporter{})
+}
+
+func TestSummaryBreakdown(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt := SummaryBreakdown(func(err error) string {
+		return "key"
+	})
+	opt(obj)
+
+	assert.Equal(t, "key", obj.breakdown(assert.AnError))
+}
+
+func TestSummaryByCode(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt := SummaryByCode()
+	opt(obj)
+
+	assert.Equal(t, "CFG1003", obj.breakdown(WithCode(assert.AnError, "CFG1003")))
+	assert.Equal(t, "", obj.breakdown(assert.AnError))
+}
+
+func TestSummaryByFile(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt := SummaryByFile()
+	opt(obj)
+
+	assert.Equal(t, "file.cfg", obj.breakdown(ErrorAt(Position{File: "file.cfg"}, "test error")))
+	assert.Equal(t, "", obj.breakdown(assert.AnError))
+}
+
+func TestSummaryAlways(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt := SummaryAlways()
+	opt(obj)
+
+	assert.True(t, obj.always)
+}
+
+func TestSummaryTemplate(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt := SummaryTemplate("{{plural .Total \"problem\" \"problems\"}}")
+	opt(obj)
+
+	assert.NotNil(t, obj.tmpl)
+	assert.NotSame(t, defaultSummary, obj.tmpl)
+}
+
+func TestSummaryTemplateError(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt := SummaryTemplate("{{.Total")
+	opt(obj)
+
+	assert.Nil(t, obj.tmpl)
+}
+
+func TestSummaryTemplateErrorKeepsTemplate(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root(), SummaryTemplate("{{.Total}} found"), SummaryTemplate("{{.Total"))
+	obj.Report(assert.AnError)
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1 found\n", out.String())
+}
+
+func TestParseSummaryTemplate(t *testing.T) {
+	obj := &SummaryReporter{}
+
+	opt, err := ParseSummaryTemplate("{{plural .Total \"problem\" \"problems\"}}")
+
+	require.NoError(t, err)
+	opt(obj)
+	assert.NotNil(t, obj.tmpl)
+}
+
+func TestParseSummaryTemplateError(t *testing.T) {
+	opt, err := ParseSummaryTemplate("{{.Total")
+
+	assert.Error(t, err)
+	assert.Nil(t, opt)
+}
+
+func TestNewSummaryReporterBase(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+
+	result := NewSummaryReporter(out, rep)
+
+	assert.Equal(t, &SummaryReporter{
+		out:  out,
+		tmpl: defaultSummary,
+		keys: map[string]*summaryCounts{},
+		rep:  rep,
+	}, result)
+}
+
+func TestNewSummaryReporterOptions(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+	var opt1Called, opt2Called *SummaryReporter
+	options := []SummaryReporterOption{
+		func(sr *SummaryReporter) {
+			opt1Called = sr
+		},
+		func(sr *SummaryReporter) {
+			opt2Called = sr
+		},
+	}
+
+	result := NewSummaryReporter(out, rep, options...)
+
+	assert.Equal(t, &SummaryReporter{
+		out:  out,
+		tmpl: defaultSummary,
+		keys: map[string]*summaryCounts{},
+		rep:  rep,
+	}, result)
+	assert.Same(t, result, opt1Called)
+	assert.Same(t, result, opt2Called)
+}
+
+func TestSummaryReporterReport(t *testing.T) {
+	err1 := WithCode(assert.AnError, "CFG1003")
+	err2 := NewWarning("test warning")
+	rep := &MockReporter{}
+	rep.On("Report", err1)
+	rep.On("Report", err2)
+	obj := &SummaryReporter{
+		breakdown: func(err error) string {
+			code, _ := CodeOf(err)
+			return code
+		},
+		keys: map[string]*summaryCounts{},
+		rep:  rep,
+	}
+
+	obj.Report(err1)
+	obj.Report(err2)
+	obj.Report(err1)
+
+	expected := summaryCounts{}
+	expected[SeverityError] = 2
+	expected[SeverityWarning] = 1
+	assert.Equal(t, expected, obj.counts)
+	keyExpected := summaryCounts{}
+	keyExpected[SeverityError] = 2
+	assert.Equal(t, map[string]*summaryCounts{"CFG1003": &keyExpected}, obj.keys)
+	rep.AssertExpectations(t)
+}
+
+func TestSummaryReporterUnwrap(t *testing.T) {
+	rep := &MockReporter{}
+	obj := &SummaryReporter{
+		rep: rep,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Equal(t, []Reporter{rep}, result)
+}
+
+func TestSummaryReporterView(t *testing.T) {
+	obj := NewSummaryReporter(&bytes.Buffer{}, Root(), SummaryByFile())
+	obj.Report(ErrorAt(Position{File: "b.cfg"}, "test error"))
+	obj.Report(WarningAt(Position{File: "a.cfg"}, "test warning"))
+	obj.Report(NewSeverity(SeverityFatal, "test fatal"))
+	obj.Report(WithPosition(NewSeverity(SeverityInfo, "test info"), Position{File: "a.cfg"}))
+
+	result := obj.view()
+
+	assert.Equal(t, &SummaryView{
+		Errors:   2,
+		Warnings: 1,
+		Total:    4,
+		Counts: map[Severity]int{
+			SeverityDebug:   0,
+			SeverityInfo:    1,
+			SeverityNotice:  0,
+			SeverityWarning: 1,
+			SeverityError:   1,
+			SeverityFatal:   1,
+		},
+		Breakdown: []SummaryEntry{
+			{Key: "a.cfg", Warnings: 1, Total: 2},
+			{Key: "b.cfg", Errors: 1, Total: 1},
+		},
+	}, result)
+}
+
+func TestSummaryReporterViewSkipsQuietKeys(t *testing.T) {
+	obj := NewSummaryReporter(&bytes.Buffer{}, Root(), SummaryByFile())
+	obj.Report(ErrorAt(Position{File: "b.cfg"}, "test error"))
+	obj.Report(WithPosition(NewSeverity(SeverityInfo, "test info"), Position{File: "a.cfg"}))
+	obj.Report(WithPosition(NewSeverity(SeverityNotice, "test notice"), Position{File: "c.cfg"}))
+
+	result := obj.view()
+
+	assert.Equal(t, []SummaryEntry{
+		{Key: "b.cfg", Errors: 1, Total: 1},
+	}, result.Breakdown)
+}
+
+func TestSummaryReporterViewQuietKeysAlways(t *testing.T) {
+	obj := NewSummaryReporter(&bytes.Buffer{}, Root(), SummaryByFile(), SummaryAlways())
+	obj.Report(ErrorAt(Position{File: "b.cfg"}, "test error"))
+	obj.Report(WithPosition(NewSeverity(SeverityInfo, "test info"), Position{File: "a.cfg"}))
+
+	result := obj.view()
+
+	assert.Equal(t, []SummaryEntry{
+		{Key: "a.cfg", Total: 1},
+		{Key: "b.cfg", Errors: 1, Total: 1},
+	}, result.Breakdown)
+}
+
+func TestSummaryReporterSummarize(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root())
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	obj.Report(assert.AnError)
+	obj.Report(NewWarning("test warning"))
+	obj.Report(NewWarning("test warning"))
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3 errors and 2 warnings generated\n", out.String())
+	assert.True(t, obj.done)
+}
+
+func TestSummaryReporterSummarizeBreakdown(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root(), SummaryByCode())
+	obj.Report(WithCode(assert.AnError, "CFG1003"))
+	obj.Report(WithCode(NewWarning("test warning"), "CFG1001"))
+	obj.Report(WithCode(NewWarning("test warning"), "CFG1003"))
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1 error and 2 warnings generated\n    CFG1001: 1 warning\n    CFG1003: 1 error and 1 warning\n", out.String())
+}
+
+func TestSummaryReporterSummarizeNothing(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root())
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+	assert.True(t, obj.done)
+}
+
+func TestSummaryReporterSummarizeOnlyNotes(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root())
+	obj.Report(NewSeverity(SeverityNotice, "a notice"))
+	obj.Report(NewSeverity(SeverityDebug, "a debug message"))
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+	assert.True(t, obj.done)
+}
+
+func TestSummaryReporterSummarizeAlwaysOnlyNotes(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root(), SummaryAlways())
+	obj.Report(NewSeverity(SeverityNotice, "a notice"))
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "no errors or warnings generated\n", out.String())
+}
+
+func TestSummaryReporterSummarizeAlways(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root(), SummaryAlways())
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "no errors or warnings generated\n", out.String())
+}
+
+func TestSummaryReporterSummarizeTemplate(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root(), SummaryTemplate("{{plural .Total \"problem\" \"problems\"}} found\n\n"))
+	obj.Report(assert.AnError)
+
+	err := obj.Summarize()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1 problem found\n", out.String())
+}
+
+func TestSummaryReporterSummarizeTemplateError(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root(), SummaryTemplate("{{.Missing}}"))
+	obj.Report(assert.AnError)
+
+	err := obj.Summarize()
+
+	assert.Error(t, err)
+	assert.Equal(t, "", out.String())
+}
+
+func TestSummaryReporterSummarizeWriteError(t *testing.T) {
+	obj := NewSummaryReporter(&failingWriter{}, Root())
+	obj.Report(assert.AnError)
+
+	err := obj.Summarize()
+
+	assert.Same(t, assert.AnError, err)
+}
+
+func TestSummaryReporterClose(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root())
+	obj.Report(assert.AnError)
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1 error generated\n", out.String())
+}
+
+func TestSummaryReporterCloseSummarized(t *testing.T) {
+	out := &bytes.Buffer{}
+	obj := NewSummaryReporter(out, Root())
+	obj.Report(assert.AnError)
+	require.NoError(t, obj.Summarize())
+
+	err := obj.Close()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1 error generated\n", out.String())
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// FormatView is the view of an error that is passed to templates
+// specified with FormatTemplate.
+type FormatView struct {
+	Severity Severity               // The severity of the error
+	Label    string                 // Upper-cased name of the severity
+	Message  string                 // The error message
+	Code     string                 // The diagnostic code, if any
+	Position *Position              // The position, if one is known
+	Scope    []string               // The scope path, if any
+	Fields   map[string]interface{} // The structured fields, if any
+	Notes    []Note                 // The related notes, if any
+	Chain    []string               // Messages of the wrapped errors
+	ID       string                 // The message ID, if any
+	Args     []interface{}          // The message arguments, if any
+	Err      error                  // The error itself
+}
+
+// NewFormatView constructs a FormatView describing an error.
+func NewFormatView(err error) *FormatView {
+	sev := SeverityOf(err)
+	obj := &FormatView{
+		Severity: sev,
+		Label:    strings.ToUpper(sev.String()),
+		Message:  err.Error(),
+		Scope:    Scope(err),
+		Fields:   Fields(err),
+		Notes:    Notes(err),
+		Chain:    chainOf(err),
+		Err:      err,
+	}
+
+	if pos, ok := PositionOf(err); ok && pos.known() {
+		obj.Position = &pos
+	}
+	obj.Code, _ = CodeOf(err)
+	obj.ID, obj.Args, _ = MessageOf(err)
+
+	return obj
+}
+
+// templateIndent is a template helper that indents each non-empty
+// line of text by the specified number of spaces.
+func templateIndent(n int, text string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// templateJSON is a template helper that encodes a value as JSON.
+func templateJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// templateFuncs contains the helper functions available to templates
+// specified with FormatTemplate.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"join": func(sep string, items []string) string {
+		return strings.Join(items, sep)
+	},
+	"scope": func(scope []string) string {
+		return joinScope(scope)
+	},
+	"fields": func(fields map[string]interface{}) string {
+		return joinFields(fields)
+	},
+	"quote":  formatValue,
+	"indent": templateIndent,
+	"json":   templateJSON,
+}
+
+// FormatTemplate specifies a text/template template for formatting
+// errors of all severities.  The template is executed with a
+// *FormatView describing the error, and may use the following helper
+// functions in addition to the standard ones:
+//
+//	upper, lower, trim   Change the case of or trim a string
+//	join SEP LIST        Join a list of strings with a separator
+//	scope SCOPE          Join a scope path, e.g., "deploy > web"
+//	fields FIELDS        Format fields as sorted key=value pairs
+//	quote VALUE          Format a field value, quoting as needed
+//	indent N TEXT        Indent each line of text by N spaces
+//	json VALUE           Encode a value as JSON
+//
+// The template controls the entire output for the error: the fields
+// and notes are not appended, nor is a source snippet included, so
+// the template should render them if they are desired.  For example,
+// the template
+//
+//	{{with .Position}}{{.}}: {{end}}{{.Label}}{{with .Code}}[{{.}}]{{end}}: {{.Message}}
+//	{{- range .Notes}}
+//	    {{.}}{{end}}
+//
+// closely resembles the default format with codes enabled.  Trailing
+// newlines are removed from the output.  If the template fails to
+// execute for a particular error, the default format is used for
+// that error.  If the template cannot be parsed, the returned option
+// does nothing, leaving the formats set by earlier options in place;
+// templates from configuration files or other external sources
+// should be parsed with ParseFormatTemplate instead, so that the
+// error can be reported.  The template may be overridden for a
+// particular severity by subsequent options, such as FormatError.
+func FormatTemplate(text string) FormatOption {
+	opt, err := ParseFormatTemplate(text)
+	if err != nil {
+		return func(f *Formatters) {}
+	}
+
+	return opt
+}
+
+// ParseFormatTemplate is similar to FormatTemplate, except that an
+// error is returned if the template cannot be parsed.  This should be
+// preferred when the template comes from an external source, such as
+// a configuration file.
+func ParseFormatTemplate(text string) (FormatOption, error) {
+	tmpl, err := template.New("kent").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(f *Formatters) {
+		for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+			f.formats[sev] = f.formatTemplate(tmpl, sev)
+			f.inline[sev] = true
+		}
+	}, nil
+}
+
+// formatTemplate constructs the FormatFunc used by FormatTemplate for
+// a severity.
+func (f *Formatters) formatTemplate(tmpl *template.Template, sev Severity) FormatFunc {
+	fallback := f.formatDefault(sev)
+
+	return func(err error) string {
+		buf := &strings.Builder{}
+		if tmpl.Execute(buf, NewFormatView(err)) != nil {
+			return fallback(err)
+		}
+
+		return strings.TrimRight(buf.String(), "\n")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Kevin L. Mitchell
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you
+// may not use this file except in compliance with the License.  You
+// may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied.  See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kent
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFormatViewBase(t *testing.T) {
+	result := NewFormatView(assert.AnError)
+
+	assert.Equal(t, &FormatView{
+		Severity: SeverityError,
+		Label:    "ERROR",
+		Message:  assert.AnError.Error(),
+		Err:      assert.AnError,
+	}, result)
+}
+
+func TestNewFormatViewFull(t *testing.T) {
+	inner := NewWarning("inner")
+	err := WithNotes(WithFields(WithScope(WithCode(WithPosition(fmt.Errorf("outer: %w", inner), Position{File: "file.cfg", Line: 3}), "CFG1003"), "deploy"), "rule", "no-dup"), Notef("a note"))
+
+	result := NewFormatView(err)
+
+	assert.Equal(t, &FormatView{
+		Severity: SeverityWarning,
+		Label:    "WARNING",
+		Message:  "outer: inner",
+		Code:     "CFG1003",
+		Position: &Position{File: "file.cfg", Line: 3},
+		Scope:    []string{"deploy"},
+		Fields:   map[string]interface{}{"rule": "no-dup"},
+		Notes:    []Note{Notef("a note")},
+		Chain:    []string{"inner"},
+		Err:      err,
+	}, result)
+}
+
+func TestNewFormatViewMessage(t *testing.T) {
+	cat := NewCatalog("en")
+	cat.Add("en", map[string]string{"dup": "duplicate key %q"})
+
+	result := NewFormatView(cat.New("dup", "foo"))
+
+	assert.Equal(t, `duplicate key "foo"`, result.Message)
+	assert.Equal(t, "dup", result.ID)
+	assert.Equal(t, []interface{}{"foo"}, result.Args)
+}
+
+func TestNewFormatViewUnknownPosition(t *testing.T) {
+	result := NewFormatView(WithPosition(assert.AnError, Position{}))
+
+	assert.Nil(t, result.Position)
+}
+
+func TestTemplateIndent(t *testing.T) {
+	result := templateIndent(2, "a\n\nb")
+
+	assert.Equal(t, "  a\n\n  b", result)
+}
+
+func TestTemplateJSON(t *testing.T) {
+	result, err := templateJSON(map[string]interface{}{"a": 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, result)
+}
+
+func TestTemplateJSONError(t *testing.T) {
+	result, err := templateJSON(func() {})
+
+	assert.Error(t, err)
+	assert.Equal(t, "", result)
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("test").Funcs(templateFuncs).Parse(
+		`{{upper "a"}} {{lower "B"}} {{trim " c "}} {{join "," .Scope}} {{scope .Scope}} {{fields .Fields}} {{quote "d e"}} {{indent 1 "f"}} {{json .Code}}`,
+	))
+	buf := &strings.Builder{}
+
+	err := tmpl.Execute(buf, &FormatView{
+		Code:   "CFG1003",
+		Scope:  []string{"deploy", "web"},
+		Fields: map[string]interface{}{"rule": "no-dup"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `A b c deploy,web deploy > web rule=no-dup "d e"  f "CFG1003"`, buf.String())
+}
+
+func TestFormatTemplate(t *testing.T) {
+	obj := &Formatters{}
+
+	opt := FormatTemplate("{{.Label}}: {{.Message}}\n")
+
+	opt(obj)
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		assert.NotNil(t, obj.formats[sev])
+		assert.True(t, obj.inline[sev])
+	}
+	assert.Equal(t, "NOTICE: test notice", obj.formats[SeverityNotice](NewSeverity(SeverityNotice, "test notice")))
+}
+
+func TestFormatTemplateParseError(t *testing.T) {
+	obj := NewFormatters(FormatError("E: %s"), FormatTemplate("{{.Label"))
+
+	result := obj.Format(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"))
+
+	assert.Equal(t, "E: test error", result)
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		assert.False(t, obj.inline[sev])
+	}
+}
+
+func TestFormatTemplateParseErrorKeepsTemplate(t *testing.T) {
+	obj := NewFormatters(FormatTemplate("{{.Label}}: {{.Message}}"), FormatTemplate("{{"))
+
+	result := obj.Format(assert.AnError)
+
+	assert.Equal(t, "ERROR: "+assert.AnError.Error(), result)
+	assert.True(t, obj.inline[SeverityError])
+}
+
+func TestFormatTemplateExecuteError(t *testing.T) {
+	obj := NewFormatters(FormatTemplate("{{.Missing}}"))
+
+	result := obj.Format(ErrorAt(Position{File: "file.cfg", Line: 3}, "test error"))
+
+	assert.Equal(t, "file.cfg:3: ERROR: test error", result)
+}
+
+func TestFormatTemplateInline(t *testing.T) {
+	out := &bytes.Buffer{}
+	rep := NewWritingReporter(out, Root(), FormatTemplate("{{.Label}}: {{.Message}}"))
+
+	rep.Report(NewWarning("test warning"))
+
+	assert.Equal(t, "WARNING: test warning\n", out.String())
+}
+
+func TestParseFormatTemplate(t *testing.T) {
+	obj := &Formatters{}
+
+	opt, err := ParseFormatTemplate("{{.Label}}: {{.Message}}\n")
+
+	require.NoError(t, err)
+	opt(obj)
+	for sev := SeverityDebug; sev <= SeverityFatal; sev++ {
+		assert.NotNil(t, obj.formats[sev])
+		assert.True(t, obj.inline[sev])
+	}
+	assert.Equal(t, "NOTICE: test notice", obj.formats[SeverityNotice](NewSeverity(SeverityNotice, "test notice")))
+}
+
+func TestParseFormatTemplateError(t *testing.T) {
+	opt, err := ParseFormatTemplate("{{.Label")
+
+	assert.Error(t, err)
+	assert.Nil(t, opt)
+}
+
+func TestFormatTemplateFormat(t *testing.T) {
+	opt := FormatTemplate(`{{with .Position}}{{.}}: {{end}}{{.Label}}{{with .Code}}[{{.}}]{{end}}: {{.Message}}
+{{- range .Notes}}
+    {{.}}{{end}}`)
+	obj := NewFormatters(opt, FormatFields(true))
+	diag := WithNotes(WithFields(WithCode(WarningAt(Position{File: "file.cfg", Line: 3}, "test warning"), "CFG1003"), "rule", "no-dup"), Notef("a note"))
+
+	result := obj.Format(diag)
+
+	assert.Equal(t, "file.cfg:3: WARNING[CFG1003]: test warning\n    note: a note", result)
+}
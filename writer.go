@@ -33,13 +33,17 @@ type WritingReporter struct {
 // stream (an io.Writer) with appropriate "ERROR:" and "WARNING:"
 // prefixes.  To use different formats for errors and warnings, pass
 // appropriate formatting options, such as FormatError or
-// FormatWarning.
+// FormatWarning; to colorize the output when writing to a terminal,
+// pass FormatColor(ColorAuto).
 func NewWritingReporter(out io.Writer, rep Reporter, formatOptions ...FormatOption) *WritingReporter {
-	return &WritingReporter{
+	obj := &WritingReporter{
 		out:    out,
 		rep:    rep,
 		format: newFormatters(formatOptions...),
 	}
+	obj.format.detectColor(out)
+
+	return obj
 }
 
 // Report is the core method of the Reporter interface.  It reports
@@ -17,6 +17,7 @@ package kent
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/klmitch/patcher"
@@ -61,6 +62,24 @@ func TestNewWritingReporterOptions(t *testing.T) {
 	}, result)
 }
 
+func TestNewWritingReporterColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	out := &bytes.Buffer{}
+	rep := &MockReporter{}
+	defer patcher.SetVar(&newFormatters, func(opts ...FormatOption) *Formatters {
+		return &Formatters{color: ColorAuto}
+	}).Install().Restore()
+	defer patcher.SetVar(&isTerminal, func(w io.Writer) bool {
+		assert.Same(t, out, w)
+		return true
+	}).Install().Restore()
+
+	result := NewWritingReporter(out, rep)
+
+	assert.True(t, result.format.colorOut)
+}
+
 func TestWritingReporterReportError(t *testing.T) {
 	rep := &MockReporter{}
 	rep.On("Report", assert.AnError)
@@ -94,6 +113,23 @@ func TestWritingReporterReportWarning(t *testing.T) {
 	rep.AssertExpectations(t)
 }
 
+func TestWritingReporterReportNotes(t *testing.T) {
+	err := WithNotes(ErrorAt(Position{File: "file.cfg", Line: 7}, "test error"), NoteAt(Position{File: "file.cfg", Line: 3}, "previously defined here"))
+	rep := &MockReporter{}
+	rep.On("Report", err)
+	out := &bytes.Buffer{}
+	obj := &WritingReporter{
+		out:    out,
+		rep:    rep,
+		format: NewFormatters(),
+	}
+
+	obj.Report(err)
+
+	assert.Equal(t, "file.cfg:7: ERROR: test error\n    file.cfg:3: note: previously defined here\n", out.String())
+	rep.AssertExpectations(t)
+}
+
 func TestWritingReporterUnwrap(t *testing.T) {
 	rep := &MockReporter{}
 	obj := &WritingReporter{